@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+)
+
+// Controller holds the operations reserved to administrators.
+type Controller interface {
+	UnlockUser(ctx *fiber.Ctx) error
+}
+
+type controller struct{}
+
+func New() Controller {
+	return &controller{}
+}
+
+// UnlockUser clears the failed logins of a user, lifting a lockout before it expires. Locks on
+// client IPs are left alone, they expire on their own.
+func (ctrl *controller) UnlockUser(ctx *fiber.Ctx) error {
+	userId, err := primitive.ObjectIDFromHex(ctx.Params("userId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	queryOption := queries.NewOptions()
+	queryOption.SetOnlyFields("username")
+	user, err := queries.NewUser(ctx.Context()).GetById(userId, queryOption)
+	if err != nil {
+		return err
+	}
+	if err = queries.NewLoginAttempt(ctx.Context()).DeleteByKey(models.LoginAttemptUsernameKey(user.Username)); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
@@ -0,0 +1,214 @@
+package attachment
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/configure"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/logging"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+	"jira-clone-api/utilities/storage"
+)
+
+var (
+	cfg    = configure.GetConfig()
+	logger = logging.GetLogger()
+)
+
+type Controller interface {
+	Create(ctx *fiber.Ctx) error
+	Confirm(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Download(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Create(ctx *fiber.Ctx) error {
+	var requestBody serializers.AttachmentCreateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	if requestBody.Size > cfg.AttachmentMaxSize {
+		return response.NewError(fiber.StatusRequestEntityTooLarge, response.ErrorOptions{
+			Data: fmt.Sprintf("Attachment must not exceed %d bytes", cfg.AttachmentMaxSize),
+		})
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "project_id")
+	issue, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), workspaceId, issueOption)
+	if err != nil {
+		return err
+	}
+	attachmentId := primitive.NewObjectID()
+	expiredAt := time.Now().Add(cfg.AttachmentUploadTimeout)
+	attachment, err := queries.NewAttachment(ctx.Context()).Create(models.Attachment{
+		ExpiredAt:   &expiredAt,
+		Filename:    requestBody.Filename,
+		ObjectKey:   fmt.Sprintf("attachments/%s/%s", workspaceId.Hex(), attachmentId.Hex()),
+		Size:        requestBody.Size,
+		WorkspaceId: workspaceId,
+		ProjectId:   issue.ProjectId,
+		IssueId:     issue.Id,
+		UploadedBy:  localService.GetUser().Id,
+		Id:          attachmentId,
+	})
+	if err != nil {
+		return err
+	}
+	uploadUrl, err := storage.GetGlobal().PresignedPutObject(attachment.UploadKey(), attachment.Size, cfg.AttachmentUploadTimeout)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "storage.GetGlobal().PresignedPutObject").Msg("attachmentController")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.AttachmentUploadResponse{
+			ExpiresAt: expiredAt,
+			UploadUrl: uploadUrl.String(),
+			Method:    http.MethodPut,
+			Headers:   map[string]string{"Content-Length": strconv.FormatInt(attachment.Size, 10)},
+			Id:        attachment.Id,
+		},
+	})
+}
+
+func (ctrl *controller) Confirm(ctx *fiber.Ctx) error {
+	localService := local.New(ctx)
+	current, err := ctrl.getAttachment(ctx, localService.GetWorkspace().Id)
+	if err != nil {
+		return err
+	}
+	if current.UploadedBy != localService.GetUser().Id {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	if current.IsConfirmed() {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Attachment is already confirmed"})
+	}
+	contentType, size, err := ctrl.service.inspectUpload(current)
+	if err != nil {
+		return err
+	}
+	if err = storage.GetGlobal().CopyObject(current.UploadKey(), current.ObjectKey, contentType); err != nil {
+		logger.Error().Err(err).Str("function", "Confirm").Str("functionInline", "storage.GetGlobal().CopyObject").Msg("attachmentController")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	attachment, err := queries.NewAttachment(ctx.Context()).ConfirmById(current.Id, contentType, size)
+	if err != nil {
+		return err
+	}
+	if err = storage.GetGlobal().DeleteObject(current.UploadKey()); err != nil {
+		logger.Error().Err(err).Str("function", "Confirm").Str("functionInline", "storage.GetGlobal().DeleteObject").Msg("attachmentController")
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*attachment),
+	})
+}
+
+func (ctrl *controller) List(ctx *fiber.Ctx) error {
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "project_id")
+	issue, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), local.New(ctx).GetWorkspace().Id, issueOption)
+	if err != nil {
+		return err
+	}
+	queryOption := queries.NewOptions()
+	queryOption.AddSortKey(map[string]int{"_id": queries.SortTypeAsc})
+	attachments, err := queries.NewAttachment(ctx.Context()).GetConfirmedByIssueId(issue.Id, queryOption)
+	if err != nil {
+		return err
+	}
+	results := make([]serializers.AttachmentResponse, len(attachments))
+	for i := 0; i < len(attachments); i++ {
+		results[i] = ctrl.service.toResponse(attachments[i])
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: results,
+	})
+}
+
+func (ctrl *controller) Download(ctx *fiber.Ctx) error {
+	attachment, err := ctrl.getAttachment(ctx, local.New(ctx).GetWorkspace().Id)
+	if err != nil {
+		return err
+	}
+	if !attachment.IsConfirmed() || attachment.ExpiredAt != nil {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Attachment not found"})
+	}
+	downloadUrl, err := storage.GetGlobal().PresignedGetObject(attachment.ObjectKey, attachment.Filename, attachment.ContentType, cfg.AttachmentDownloadTimeout)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "Download").Str("functionInline", "storage.GetGlobal().PresignedGetObject").Msg("attachmentController")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.AttachmentDownloadResponse{
+			ExpiresAt: time.Now().Add(cfg.AttachmentDownloadTimeout),
+			Url:       downloadUrl.String(),
+		},
+	})
+}
+
+func (ctrl *controller) Delete(ctx *fiber.Ctx) error {
+	localService := local.New(ctx)
+	attachment, err := ctrl.getAttachment(ctx, localService.GetWorkspace().Id)
+	if err != nil {
+		return err
+	}
+	role := localService.GetWorkspaceRole()
+	if attachment.UploadedBy != localService.GetUser().Id && role != constants.WorkspaceRoleOwner && role != constants.WorkspaceRoleAdmin {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	for _, key := range attachment.ObjectKeys() {
+		if err = storage.GetGlobal().DeleteObject(key); err != nil {
+			logger.Error().Err(err).Str("function", "Delete").Str("functionInline", "storage.GetGlobal().DeleteObject").Msg("attachmentController")
+			return response.NewError(fiber.StatusInternalServerError)
+		}
+	}
+	if err = queries.NewAttachment(ctx.Context()).DeleteById(attachment.Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) getAttachment(ctx *fiber.Ctx, workspaceId primitive.ObjectID) (*models.Attachment, error) {
+	attachmentId, err := primitive.ObjectIDFromHex(ctx.Params("attachmentId"))
+	if err != nil {
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "project_id")
+	issue, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), workspaceId, issueOption)
+	if err != nil {
+		return nil, err
+	}
+	return queries.NewAttachment(ctx.Context()).GetByIdAndIssueId(attachmentId, issue.Id)
+}
@@ -0,0 +1,58 @@
+package attachment
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/utilities/storage"
+)
+
+// sniffLength is how many bytes http.DetectContentType looks at.
+const sniffLength = 512
+
+type serviceInterface interface {
+	inspectUpload(attachment *models.Attachment) (contentType string, size int64, err error)
+	toResponse(attachment models.Attachment) serializers.AttachmentResponse
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// inspectUpload checks the uploaded object against the size announced when the upload URL was
+// signed and detects its content type from the first bytes rather than trusting the client.
+func (s *service) inspectUpload(attachment *models.Attachment) (string, int64, error) {
+	store := storage.GetGlobal()
+	size, err := store.StatObject(attachment.UploadKey())
+	if err != nil {
+		return "", 0, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "File has not been uploaded yet"})
+	}
+	if size != attachment.Size {
+		if err = store.DeleteObject(attachment.UploadKey()); err != nil {
+			logger.Error().Err(err).Str("function", "inspectUpload").Str("functionInline", "store.DeleteObject").Msg("attachmentService")
+		}
+		return "", 0, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Uploaded file size does not match the announced size"})
+	}
+	head, err := store.ReadObjectHead(attachment.UploadKey(), sniffLength)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "inspectUpload").Str("functionInline", "store.ReadObjectHead").Msg("attachmentService")
+		return "", 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return http.DetectContentType(head), size, nil
+}
+
+func (s *service) toResponse(attachment models.Attachment) serializers.AttachmentResponse {
+	return serializers.AttachmentResponse{
+		CreatedAt:   attachment.CreatedAt,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		UploadedBy:  attachment.UploadedBy,
+		Id:          attachment.Id,
+	}
+}
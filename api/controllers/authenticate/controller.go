@@ -1,12 +1,17 @@
 package authenticate
 
 import (
+	"crypto/subtle"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"golang.org/x/crypto/bcrypt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"jira-clone-api/api/serializers"
 	"jira-clone-api/common/configure"
+	"jira-clone-api/common/constants"
 	"jira-clone-api/common/logging"
 	"jira-clone-api/common/response"
 	respErr "jira-clone-api/common/response/error"
@@ -14,6 +19,9 @@ import (
 	"jira-clone-api/database/mongo/queries"
 	"jira-clone-api/utilities/jwt"
 	"jira-clone-api/utilities/local"
+	"jira-clone-api/utilities/password"
+	"jira-clone-api/utilities/sso"
+	"jira-clone-api/utilities/totp"
 )
 
 var (
@@ -21,10 +29,30 @@ var (
 	logger = logging.GetLogger()
 )
 
+// ssoStateCookie binds a single sign-on state to the browser that started the login.
+const ssoStateCookie = "sso_state"
+
 type Controller interface {
 	Login(ctx *fiber.Ctx) error
 	Register(ctx *fiber.Ctx) error
+	Refresh(ctx *fiber.Ctx) error
+	Logout(ctx *fiber.Ctx) error
+	LogoutAll(ctx *fiber.Ctx) error
+	GetSessions(ctx *fiber.Ctx) error
+	RevokeSession(ctx *fiber.Ctx) error
 	GetUserInfo(ctx *fiber.Ctx) error
+	SendVerificationEmail(ctx *fiber.Ctx) error
+	VerifyEmail(ctx *fiber.Ctx) error
+	ForgotPassword(ctx *fiber.Ctx) error
+	ResetPassword(ctx *fiber.Ctx) error
+	VerifyMfa(ctx *fiber.Ctx) error
+	EnrollMfa(ctx *fiber.Ctx) error
+	EnableMfa(ctx *fiber.Ctx) error
+	DisableMfa(ctx *fiber.Ctx) error
+	RegenerateMfaRecoveryCodes(ctx *fiber.Ctx) error
+	GetSsoProviders(ctx *fiber.Ctx) error
+	AuthorizeSso(ctx *fiber.Ctx) error
+	CallbackSso(ctx *fiber.Ctx) error
 }
 
 type controller struct {
@@ -47,20 +75,21 @@ func (ctrl *controller) Register(ctx *fiber.Ctx) error {
 	if err := requestBody.Validate(); err != nil {
 		return err
 	}
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(requestBody.Password), bcrypt.DefaultCost)
+	hashedPassword, err := password.New().Hash(requestBody.Password)
 	if err != nil {
-		logger.Error().Err(err).Str("function", "Login").Str("functionInline", "jwt.GetGlobal().GenerateFromPassword").Msg("authenticateController")
+		logger.Error().Err(err).Str("function", "Register").Str("functionInline", "password.New().Hash").Msg("authenticateController")
 		return response.New(ctx, response.Options{Code: fiber.StatusInternalServerError})
-
 	}
 	user, err := queries.NewUser(ctx.Context()).Create(models.User{
 		Username: requestBody.Username,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 		Email:    requestBody.Email,
 	})
 	if err != nil {
 		return err
 	}
+	// The account exists either way; a failed delivery is logged and the user can ask for another link.
+	_ = ctrl.service.sendVerificationEmail(ctx.Context(), *user)
 	return response.New(ctx, response.Options{
 		Code: fiber.StatusOK,
 		Data: fiber.Map{
@@ -79,34 +108,56 @@ func (ctrl *controller) Login(ctx *fiber.Ctx) error {
 	if err := requestBody.Validate(); err != nil {
 		return err
 	}
+	attemptKeys := []string{models.LoginAttemptUsernameKey(requestBody.Username), models.LoginAttemptIpKey(ctx.IP())}
+	if err := ctrl.service.checkLoginLockout(ctx, attemptKeys); err != nil {
+		return err
+	}
 	optionQuery := queries.NewOptions()
-	optionQuery.SetOnlyFields("password")
+	optionQuery.SetOnlyFields("password", "email_verified_at", "mfa_enabled_at")
 	user, err := queries.NewUser(ctx.Context()).GetByUsername(requestBody.Username, optionQuery)
-	if err != nil {
+	if e := new(response.Error); err != nil && (!errors.As(err, &e) || e.Code != fiber.StatusNotFound) {
 		return err
 	}
-	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(requestBody.Password)); err != nil {
-		logger.Error().Err(err).Str("function", "Login").Str("functionInline", "jwt.GetGlobal().CompareHashAndPassword").Msg("authenticateController")
-		return response.New(ctx, response.Options{Code: fiber.StatusUnauthorized, Data: "Invalid password"})
+	if !ctrl.service.verifyPassword(user, requestBody.Password) {
+		return ctrl.service.recordLoginFailure(ctx.Context(), attemptKeys, respErr.ErrLoginFailed)
 	}
-	tokenId, err := queries.NewToken(ctx.Context()).Create(models.Token{
-		ExpiredAt: time.Now().Add(time.Hour * 5),
+	ctrl.service.rehashPassword(ctx.Context(), *user, requestBody.Password)
+	if cfg.RequireVerifiedEmail && !user.IsEmailVerified() {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrEmailNotVerified})
+	}
+	if user.IsMfaEnabled() {
+		// The failures are only reset once the second factor is passed too, otherwise a known password
+		// would lift the lockout on guessing codes.
+		pendingToken, err := ctrl.service.issueMfaPendingToken(user.Id)
+		if err != nil {
+			return err
+		}
+		return response.New(ctx, response.Options{Code: fiber.StatusOK, Data: pendingToken})
+	}
+	ctrl.service.resetLoginFailures(ctx.Context(), attemptKeys[0])
+	pairToken, err := ctrl.service.issuePairToken(ctx.Context(), models.Token{
+		UserAgent: ctx.Get(fiber.HeaderUserAgent),
+		IpAddress: ctx.IP(),
 		UserId:    user.Id,
+		FamilyId:  primitive.NewObjectID(),
 	})
 	if err != nil {
 		return err
 	}
-	accessToken, _, err := jwt.GetGlobal().GeneratePairToken(tokenId.Hex(), cfg.AccessTokenTimeout, cfg.RefreshTokenTimeout)
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: pairToken,
+	})
+}
+
+func (ctrl *controller) Refresh(ctx *fiber.Ctx) error {
+	pairToken, err := ctrl.service.rotatePairToken(ctx.Context(), local.New(ctx).GetTokenId(), ctx.Get(fiber.HeaderUserAgent), ctx.IP())
 	if err != nil {
-		logger.Error().Err(err).Str("function", "Login").Str("functionInline", "jwt.GetGlobal().GeneratePairToken").Msg("authenticateController")
-		return response.New(ctx, response.Options{Code: fiber.StatusInternalServerError})
+		return err
 	}
 	return response.New(ctx, response.Options{
 		Code: fiber.StatusOK,
-		Data: serializers.AuthenticateLoginResponse{
-			AccessToken: accessToken,
-			TokenType:   cfg.TokenType,
-		},
+		Data: pairToken,
 	})
 }
 
@@ -115,9 +166,458 @@ func (ctrl *controller) GetUserInfo(ctx *fiber.Ctx) error {
 	return response.New(ctx, response.Options{
 		Code: fiber.StatusOK,
 		Data: serializers.AuthenticateGetUserInfoResponse{
-			Username: user.Username,
-			Email:    user.Email,
-			Id:       user.Id,
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: user.IsEmailVerified(),
+			MfaEnabled:    user.IsMfaEnabled(),
+			Id:            user.Id,
 		},
 	})
 }
+
+func (ctrl *controller) Logout(ctx *fiber.Ctx) error {
+	if err := queries.NewToken(ctx.Context()).DeleteById(local.New(ctx).GetTokenId()); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) LogoutAll(ctx *fiber.Ctx) error {
+	if err := queries.NewToken(ctx.Context()).DeleteByUserId(local.New(ctx).GetUser().Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) GetSessions(ctx *fiber.Ctx) error {
+	localService := local.New(ctx)
+	queryOption := queries.NewOptions()
+	queryOption.AddSortKey(map[string]int{"last_used_at": queries.SortTypeDesc})
+	queryOption.SetOnlyFields("_id", "created_at", "last_used_at", "user_agent", "ip_address")
+	tokens, err := queries.NewToken(ctx.Context()).GetActiveByUserId(localService.GetUser().Id, queryOption)
+	if err != nil {
+		return err
+	}
+	currentTokenId := localService.GetTokenId()
+	results := make([]serializers.AuthenticateSessionResponseItem, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		results[i].CreatedAt = tokens[i].CreatedAt
+		results[i].LastUsedAt = tokens[i].LastUsedAt
+		results[i].UserAgent = tokens[i].UserAgent
+		results[i].IpAddress = tokens[i].IpAddress
+		results[i].Id = tokens[i].Id
+		results[i].IsCurrent = tokens[i].Id == currentTokenId
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: results,
+	})
+}
+
+func (ctrl *controller) RevokeSession(ctx *fiber.Ctx) error {
+	sessionId, err := primitive.ObjectIDFromHex(ctx.Params("id"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	if err = queries.NewToken(ctx.Context()).DeleteByIdAndUserId(sessionId, local.New(ctx).GetUser().Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) SendVerificationEmail(ctx *fiber.Ctx) error {
+	user := local.New(ctx).GetUser()
+	if user.IsEmailVerified() {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: respErr.ErrEmailAlreadyVerified})
+	}
+	if err := ctrl.service.sendVerificationEmail(ctx.Context(), user); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+// VerifyEmail marks the email of the token owner as verified. The token is the proof, so the caller
+// does not need to be logged in.
+func (ctrl *controller) VerifyEmail(ctx *fiber.Ctx) error {
+	var requestBody serializers.AuthenticateVerifyEmailBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	userToken, err := ctrl.service.useUserToken(ctx.Context(), requestBody.Token, constants.UserTokenEmailVerification)
+	if err != nil {
+		return err
+	}
+	if _, err = queries.NewUser(ctx.Context()).UpdateById(userToken.UserId, bson.M{"email_verified_at": time.Now()}); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+// ForgotPassword mails a reset link to every account registered with the email. It answers the same
+// whether or not such an account exists, so it cannot be used to find out who is registered.
+func (ctrl *controller) ForgotPassword(ctx *fiber.Ctx) error {
+	var requestBody serializers.AuthenticateForgotPasswordBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	queryOption := queries.NewOptions()
+	queryOption.SetOnlyFields("_id", "username", "email")
+	users, err := queries.NewUser(ctx.Context()).GetByEmail(requestBody.Email, queryOption)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		_ = ctrl.service.sendPasswordResetEmail(ctx.Context(), user)
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+// ResetPassword sets a new password and ends every session of the account, so whoever knew the
+// old password is logged out too. Receiving the link also proves the email address.
+func (ctrl *controller) ResetPassword(ctx *fiber.Ctx) error {
+	var requestBody serializers.AuthenticateResetPasswordBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	userToken, err := ctrl.service.useUserToken(ctx.Context(), requestBody.Token, constants.UserTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := password.New().Hash(requestBody.Password)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "ResetPassword").Str("functionInline", "password.New().Hash").Msg("authenticateController")
+		return response.New(ctx, response.Options{Code: fiber.StatusInternalServerError})
+	}
+	userQuery := queries.NewUser(ctx.Context())
+	user, err := userQuery.GetById(userToken.UserId, queries.NewOptions())
+	if err != nil {
+		return err
+	}
+	data := bson.M{"password": hashedPassword}
+	if !user.IsEmailVerified() {
+		data["email_verified_at"] = time.Now()
+	}
+	if _, err = userQuery.UpdateById(user.Id, data); err != nil {
+		return err
+	}
+	if err = queries.NewToken(ctx.Context()).DeleteByUserId(user.Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+// VerifyMfa is the second step of a login with two-factor authentication: it exchanges the pending
+// token handed out by Login and a TOTP or recovery code for a token pair.
+func (ctrl *controller) VerifyMfa(ctx *fiber.Ctx) error {
+	var requestBody serializers.AuthenticateMfaVerifyBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	transactionId, err := jwt.GetGlobal().ValidateTransactionToken(requestBody.MfaToken, jwt.TransactionMfaPending)
+	if err != nil {
+		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenWrong})
+	}
+	userId, err := primitive.ObjectIDFromHex(transactionId)
+	if err != nil {
+		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenWrong})
+	}
+	optionQuery := queries.NewOptions()
+	optionQuery.SetOnlyFields("_id", "username", "mfa_enabled_at", "mfa_secret")
+	user, err := queries.NewUser(ctx.Context()).GetById(userId, optionQuery)
+	if err != nil || !user.IsMfaEnabled() {
+		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenWrong})
+	}
+	if err = ctrl.service.checkMfaCode(ctx, *user, requestBody.Code); err != nil {
+		return err
+	}
+	pairToken, err := ctrl.service.issuePairToken(ctx.Context(), models.Token{
+		UserAgent: ctx.Get(fiber.HeaderUserAgent),
+		IpAddress: ctx.IP(),
+		UserId:    user.Id,
+		FamilyId:  primitive.NewObjectID(),
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: pairToken,
+	})
+}
+
+// EnrollMfa generates a new TOTP secret for the current user. Logins do not ask for it until
+// EnableMfa has confirmed that the authenticator app produces valid codes for it.
+func (ctrl *controller) EnrollMfa(ctx *fiber.Ctx) error {
+	user := local.New(ctx).GetUser()
+	if user.IsMfaEnabled() {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: respErr.ErrMfaAlreadyEnabled})
+	}
+	totpService := totp.New()
+	secret, err := totpService.GenerateSecret()
+	if err != nil {
+		logger.Error().Err(err).Str("function", "EnrollMfa").Str("functionInline", "totpService.GenerateSecret").Msg("authenticateController")
+		return response.New(ctx, response.Options{Code: fiber.StatusInternalServerError})
+	}
+	if _, err = queries.NewUser(ctx.Context()).UpdateById(user.Id, bson.M{"mfa_secret": secret}); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.AuthenticateMfaEnrollResponse{
+			Secret: secret,
+			Uri:    totpService.URI(cfg.MfaIssuer, user.Username, secret),
+		},
+	})
+}
+
+// EnableMfa turns two-factor authentication on once the code proves the enrolled secret works, and
+// returns the recovery codes. They are only stored hashed, so this is the one time they are shown.
+func (ctrl *controller) EnableMfa(ctx *fiber.Ctx) error {
+	var requestBody serializers.AuthenticateMfaCodeBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	user, err := ctrl.getMfaUser(ctx)
+	if err != nil {
+		return err
+	}
+	if user.IsMfaEnabled() {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: respErr.ErrMfaAlreadyEnabled})
+	}
+	if user.MfaSecret == "" {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrMfaNotEnrolled})
+	}
+	if err = ctrl.service.checkMfaCode(ctx, *user, requestBody.Code); err != nil {
+		return err
+	}
+	codes, hashes, err := ctrl.service.generateRecoveryCodes()
+	if err != nil {
+		return err
+	}
+	if _, err = queries.NewUser(ctx.Context()).UpdateById(user.Id, bson.M{"mfa_enabled_at": time.Now(), "mfa_recovery_codes": hashes}); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.AuthenticateMfaRecoveryCodesResponse{RecoveryCodes: codes},
+	})
+}
+
+// DisableMfa asks for both the password and a code, so neither a stolen session nor a stolen
+// password alone can remove the second factor.
+func (ctrl *controller) DisableMfa(ctx *fiber.Ctx) error {
+	var requestBody serializers.AuthenticateMfaDisableBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	user, err := ctrl.getMfaUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.IsMfaEnabled() {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrMfaNotEnabled})
+	}
+	if !ctrl.service.verifyPassword(user, requestBody.Password) {
+		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrPasswordWrong})
+	}
+	if err = ctrl.service.checkMfaCode(ctx, *user, requestBody.Code); err != nil {
+		return err
+	}
+	if err = queries.NewUser(ctx.Context()).DisableMfaById(user.Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+// RegenerateMfaRecoveryCodes replaces every recovery code, used or not, with a fresh set.
+func (ctrl *controller) RegenerateMfaRecoveryCodes(ctx *fiber.Ctx) error {
+	var requestBody serializers.AuthenticateMfaCodeBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	user, err := ctrl.getMfaUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.IsMfaEnabled() {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrMfaNotEnabled})
+	}
+	if err = ctrl.service.checkMfaCode(ctx, *user, requestBody.Code); err != nil {
+		return err
+	}
+	codes, hashes, err := ctrl.service.generateRecoveryCodes()
+	if err != nil {
+		return err
+	}
+	if _, err = queries.NewUser(ctx.Context()).UpdateById(user.Id, bson.M{"mfa_recovery_codes": hashes}); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.AuthenticateMfaRecoveryCodesResponse{RecoveryCodes: codes},
+	})
+}
+
+// getMfaUser loads the fields of the current user that the middleware leaves out on purpose.
+func (ctrl *controller) getMfaUser(ctx *fiber.Ctx) (*models.User, error) {
+	optionQuery := queries.NewOptions()
+	optionQuery.SetOnlyFields("_id", "username", "password", "mfa_enabled_at", "mfa_secret")
+	return queries.NewUser(ctx.Context()).GetById(local.New(ctx).GetUser().Id, optionQuery)
+}
+
+// GetSsoProviders lists the single sign-on providers the login page can offer.
+func (ctrl *controller) GetSsoProviders(ctx *fiber.Ctx) error {
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: sso.GetGlobal().Providers(),
+	})
+}
+
+// AuthorizeSso starts a single sign-on login and returns the provider URL to send the browser to.
+// The provider redirects back to the web app, which hands the code and state to CallbackSso. The
+// state is also set in an HttpOnly cookie that CallbackSso requires, so a code and state from an
+// attacker's own login cannot sign another browser into the attacker's account. The web app must
+// call both endpoints with credentials, from the API origin or one listed in CORS_ALLOW_ORIGINS.
+func (ctrl *controller) AuthorizeSso(ctx *fiber.Ctx) error {
+	providerName := ctx.Params("provider")
+	provider, err := sso.GetGlobal().Provider(providerName)
+	if err != nil {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: respErr.ErrSsoProviderNotFound})
+	}
+	authRequest, err := sso.GetGlobal().NewAuthRequest()
+	if err != nil {
+		logger.Error().Err(err).Str("function", "AuthorizeSso").Str("functionInline", "sso.GetGlobal().NewAuthRequest").Msg("authenticateController")
+		return response.New(ctx, response.Options{Code: fiber.StatusInternalServerError})
+	}
+	authorizationUrl, err := provider.AuthCodeURL(ctx.Context(), authRequest)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "AuthorizeSso").Str("functionInline", "provider.AuthCodeURL").Msg("authenticateController")
+		return response.NewError(fiber.StatusBadGateway, response.ErrorOptions{Data: respErr.ErrSsoFailed})
+	}
+	if _, err = queries.NewSsoState(ctx.Context()).Create(models.SsoState{
+		ExpiredAt:    time.Now().Add(cfg.SsoStateTimeout),
+		Provider:     providerName,
+		State:        authRequest.State,
+		Nonce:        authRequest.Nonce,
+		CodeVerifier: authRequest.CodeVerifier,
+	}); err != nil {
+		return err
+	}
+	ctx.Cookie(&fiber.Cookie{
+		Name:     ssoStateCookie,
+		Value:    authRequest.State,
+		Path:     ssoCookiePath(ctx),
+		Expires:  time.Now().Add(cfg.SsoStateTimeout),
+		Secure:   ctx.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.AuthenticateSsoAuthorizeResponse{
+			AuthorizationUrl: authorizationUrl,
+			State:            authRequest.State,
+		},
+	})
+}
+
+// CallbackSso finishes a single sign-on login. Like Login, it answers with a pending token instead of
+// a token pair when the user has two-factor authentication enabled.
+func (ctrl *controller) CallbackSso(ctx *fiber.Ctx) error {
+	var requestBody serializers.AuthenticateSsoCallbackBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	providerName := ctx.Params("provider")
+	provider, err := sso.GetGlobal().Provider(providerName)
+	if err != nil {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: respErr.ErrSsoProviderNotFound})
+	}
+	ssoState, err := queries.NewSsoState(ctx.Context()).DeleteByStateAndProvider(requestBody.State, providerName)
+	if err != nil {
+		return err
+	}
+	browserState := ctx.Cookies(ssoStateCookie)
+	ctx.Cookie(&fiber.Cookie{Name: ssoStateCookie, Path: ssoCookiePath(ctx), Expires: time.Unix(0, 0), HTTPOnly: true})
+	if subtle.ConstantTimeCompare([]byte(browserState), []byte(ssoState.State)) != 1 {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrSsoStateWrong})
+	}
+	identity, err := provider.Exchange(ctx.Context(), requestBody.Code, sso.AuthRequest{
+		State:        ssoState.State,
+		Nonce:        ssoState.Nonce,
+		CodeVerifier: ssoState.CodeVerifier,
+	})
+	if err != nil {
+		logger.Warn().Err(err).Str("function", "CallbackSso").Str("functionInline", "provider.Exchange").Str("provider", providerName).Msg("authenticateController")
+		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrSsoFailed})
+	}
+	user, err := ctrl.service.resolveSsoUser(ctx.Context(), providerName, *identity)
+	if err != nil {
+		return err
+	}
+	if user.IsMfaEnabled() {
+		pendingToken, err := ctrl.service.issueMfaPendingToken(user.Id)
+		if err != nil {
+			return err
+		}
+		return response.New(ctx, response.Options{Code: fiber.StatusOK, Data: pendingToken})
+	}
+	pairToken, err := ctrl.service.issuePairToken(ctx.Context(), models.Token{
+		UserAgent: ctx.Get(fiber.HeaderUserAgent),
+		IpAddress: ctx.IP(),
+		UserId:    user.Id,
+		FamilyId:  primitive.NewObjectID(),
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: pairToken,
+	})
+}
+
+// ssoCookiePath scopes the state cookie to the SSO routes of the provider in the request path.
+func ssoCookiePath(ctx *fiber.Ctx) string {
+	return strings.TrimSuffix(strings.TrimSuffix(ctx.Path(), "/authorize"), "/callback")
+}
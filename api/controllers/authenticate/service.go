@@ -1,9 +1,470 @@
 package authenticate
 
-type serviceInterface interface{}
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/jwt"
+	"jira-clone-api/utilities/mailer"
+	"jira-clone-api/utilities/password"
+	"jira-clone-api/utilities/sso"
+	"jira-clone-api/utilities/totp"
+)
+
+type serviceInterface interface {
+	issuePairToken(ctx context.Context, session models.Token) (*serializers.AuthenticateLoginResponse, error)
+	rotatePairToken(ctx context.Context, tokenId primitive.ObjectID, userAgent, ipAddress string) (*serializers.AuthenticateLoginResponse, error)
+	sendVerificationEmail(ctx context.Context, user models.User) error
+	sendPasswordResetEmail(ctx context.Context, user models.User) error
+	useUserToken(ctx context.Context, secret, purpose string) (*models.UserToken, error)
+	rehashPassword(ctx context.Context, user models.User, plainPassword string)
+	verifyPassword(user *models.User, plainPassword string) bool
+	checkLoginLockout(ctx *fiber.Ctx, keys []string) error
+	recordLoginFailure(ctx context.Context, keys []string, message string) error
+	resetLoginFailures(ctx context.Context, key string)
+	issueMfaPendingToken(userId primitive.ObjectID) (*serializers.AuthenticateMfaPendingResponse, error)
+	checkMfaCode(ctx *fiber.Ctx, user models.User, code string) error
+	generateRecoveryCodes() (codes, hashes []string, err error)
+	resolveSsoUser(ctx context.Context, provider string, identity sso.Identity) (*models.User, error)
+}
+
+const (
+	mfaRecoveryCodeCount = 10
+	// ssoUsernameAttempts is how many usernames are tried for a new single sign-on user before giving up.
+	ssoUsernameAttempts  = 5
+	ssoUsernameMaxLength = 32
+)
+
+// dummyPasswordHash is verified against when the username does not exist, so that a login for an
+// unknown user takes as long as one with a wrong password.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hashedPassword, _ := password.New().Hash(primitive.NewObjectID().Hex())
+	return hashedPassword
+})
 
 type service struct{}
 
 func newService() serviceInterface {
 	return &service{}
 }
+
+// issuePairToken stores the session as a new token document and signs an access/refresh pair for it.
+// The session must carry the user, the token family and the client it was issued to.
+func (s *service) issuePairToken(ctx context.Context, session models.Token) (*serializers.AuthenticateLoginResponse, error) {
+	session.ExpiredAt = time.Now().Add(cfg.RefreshTokenTimeout)
+	tokenId, err := queries.NewToken(ctx).Create(session)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, refreshToken, err := jwt.GetGlobal().GeneratePairToken(tokenId.Hex(), cfg.AccessTokenTimeout, cfg.RefreshTokenTimeout)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "issuePairToken").Str("functionInline", "jwt.GetGlobal().GeneratePairToken").Msg("authenticateService")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &serializers.AuthenticateLoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    cfg.TokenType,
+	}, nil
+}
+
+// rotatePairToken revokes the given token and issues its successor in the same family.
+// If the token was already revoked, the refresh token has been replayed and the whole family is dropped.
+func (s *service) rotatePairToken(ctx context.Context, tokenId primitive.ObjectID, userAgent, ipAddress string) (*serializers.AuthenticateLoginResponse, error) {
+	tokenQuery := queries.NewToken(ctx)
+	previous, err := tokenQuery.RevokeById(tokenId)
+	if err != nil {
+		return nil, err
+	}
+	if previous.IsRevoked() {
+		if err = tokenQuery.DeleteByFamilyId(previous.FamilyId); err != nil {
+			return nil, err
+		}
+		return nil, response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenRevoked})
+	}
+	return s.issuePairToken(ctx, models.Token{
+		UserAgent: userAgent,
+		IpAddress: ipAddress,
+		UserId:    previous.UserId,
+		FamilyId:  previous.FamilyId,
+	})
+}
+
+func (s *service) sendVerificationEmail(ctx context.Context, user models.User) error {
+	secret, err := s.issueUserToken(ctx, user.Id, constants.UserTokenEmailVerification, cfg.EmailVerificationTimeout)
+	if err != nil {
+		return err
+	}
+	return s.sendMail(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm that this is your email address by opening the link below. It expires in %s.\n\n%s\n\nIf you did not create an account, you can ignore this email.\n",
+			user.Username, readableDuration(cfg.EmailVerificationTimeout), userTokenLink("/verify-email", secret)),
+	})
+}
+
+func (s *service) sendPasswordResetEmail(ctx context.Context, user models.User) error {
+	secret, err := s.issueUserToken(ctx, user.Id, constants.UserTokenPasswordReset, cfg.PasswordResetTimeout)
+	if err != nil {
+		return err
+	}
+	return s.sendMail(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. Open the link below to choose a new one. It expires in %s and works once.\n\n%s\n\nIf it was not you, you can ignore this email; your password stays the same.\n",
+			user.Username, readableDuration(cfg.PasswordResetTimeout), userTokenLink("/reset-password", secret)),
+	})
+}
+
+// useUserToken consumes a mailed secret issued for purpose.
+func (s *service) useUserToken(ctx context.Context, secret, purpose string) (*models.UserToken, error) {
+	return queries.NewUserToken(ctx).UseByHashAndPurpose(hashUserToken(secret), purpose)
+}
+
+// issueUserToken replaces the pending tokens of the user for purpose with a new one and returns its
+// secret. Only the hash is stored, so the secret exists nowhere but in the email.
+func (s *service) issueUserToken(ctx context.Context, userId primitive.ObjectID, purpose string, timeout time.Duration) (string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		logger.Error().Err(err).Str("function", "issueUserToken").Str("functionInline", "rand.Read").Msg("authenticateService")
+		return "", response.NewError(fiber.StatusInternalServerError)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+	userTokenQuery := queries.NewUserToken(ctx)
+	if err := userTokenQuery.DeleteByUserIdAndPurpose(userId, purpose); err != nil {
+		return "", err
+	}
+	if _, err := userTokenQuery.Create(models.UserToken{
+		ExpiredAt: time.Now().Add(timeout),
+		Purpose:   purpose,
+		Hash:      hashUserToken(secret),
+		UserId:    userId,
+	}); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (s *service) sendMail(message mailer.Message) error {
+	if err := mailer.GetGlobal().Send(message); err != nil {
+		logger.Error().Err(err).Str("function", "sendMail").Str("functionInline", "mailer.GetGlobal().Send").Msg("authenticateService")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+// rehashPassword upgrades a bcrypt hash, or an Argon2id hash with outdated parameters, right after the
+// user proved the password. A failure only delays the upgrade to the next login.
+func (s *service) rehashPassword(ctx context.Context, user models.User, plainPassword string) {
+	if !password.New().NeedsRehash(user.Password) {
+		return
+	}
+	hashedPassword, err := password.New().Hash(plainPassword)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "rehashPassword").Str("functionInline", "password.New().Hash").Msg("authenticateService")
+		return
+	}
+	_, _ = queries.NewUser(ctx).UpdateById(user.Id, bson.M{"password": hashedPassword})
+}
+
+// verifyPassword reports whether plainPassword belongs to user, which is nil for an unknown username.
+func (s *service) verifyPassword(user *models.User, plainPassword string) bool {
+	// Users created through single sign-on have no password until they reset one.
+	hasPassword := user != nil && user.Password != ""
+	encoded := dummyPasswordHash()
+	if hasPassword {
+		encoded = user.Password
+	}
+	ok, err := password.New().Verify(plainPassword, encoded)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "verifyPassword").Str("functionInline", "password.New().Verify").Msg("authenticateService")
+	}
+	return ok && hasPassword
+}
+
+// checkLoginLockout rejects the login while the username or the client IP is locked, telling the
+// client through Retry-After when to try again.
+func (s *service) checkLoginLockout(ctx *fiber.Ctx, keys []string) error {
+	loginAttempts, err := queries.NewLoginAttempt(ctx.Context()).GetLockedByKeys(keys)
+	if err != nil || len(loginAttempts) == 0 {
+		return err
+	}
+	var lockedUntil time.Time
+	for _, loginAttempt := range loginAttempts {
+		if loginAttempt.LockedUntil.After(lockedUntil) {
+			lockedUntil = *loginAttempt.LockedUntil
+		}
+	}
+	ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(time.Until(lockedUntil).Seconds()))))
+	return response.NewError(fiber.StatusTooManyRequests, response.ErrorOptions{Data: respErr.ErrLoginLocked})
+}
+
+// recordLoginFailure counts the failure against every key, locks the keys that went over their
+// threshold and returns a 401 carrying message. Login passes the same message for unknown users and
+// wrong passwords.
+func (s *service) recordLoginFailure(ctx context.Context, keys []string, message string) error {
+	loginAttemptQuery := queries.NewLoginAttempt(ctx)
+	for _, key := range keys {
+		loginAttempt, err := loginAttemptQuery.IncreaseFailuresByKey(key, cfg.LoginAttemptWindow)
+		if err != nil {
+			return err
+		}
+		threshold := cfg.LoginMaxFailures
+		if strings.HasPrefix(key, models.LoginAttemptIpKey("")) {
+			threshold = cfg.LoginIpMaxFailures
+		}
+		if lockout := loginLockout(loginAttempt.Failures, threshold); lockout > 0 {
+			if err = loginAttemptQuery.LockByKey(key, time.Now().Add(lockout)); err != nil {
+				return err
+			}
+		}
+	}
+	return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: message})
+}
+
+// resetLoginFailures forgets the failures of key after a successful login. Only the username is reset:
+// an attacker who owns one account must not be able to clear the counter of their IP with it.
+func (s *service) resetLoginFailures(ctx context.Context, key string) {
+	_ = queries.NewLoginAttempt(ctx).DeleteByKey(key)
+}
+
+func (s *service) issueMfaPendingToken(userId primitive.ObjectID) (*serializers.AuthenticateMfaPendingResponse, error) {
+	mfaToken, err := jwt.GetGlobal().GenerateTransactionToken(userId.Hex(), jwt.TransactionMfaPending, cfg.MfaPendingTokenTimeout)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "issueMfaPendingToken").Str("functionInline", "jwt.GetGlobal().GenerateTransactionToken").Msg("authenticateService")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &serializers.AuthenticateMfaPendingResponse{MfaRequired: true, MfaToken: mfaToken}, nil
+}
+
+// checkMfaCode accepts a TOTP code, or once MFA is enabled one of the recovery codes, and uses it up.
+// Wrong codes count as failed logins, so the lockout also stops guessing codes with a known password.
+// The user must carry its username and MFA fields.
+func (s *service) checkMfaCode(ctx *fiber.Ctx, user models.User, code string) error {
+	attemptKeys := []string{models.LoginAttemptUsernameKey(user.Username), models.LoginAttemptIpKey(ctx.IP())}
+	if err := s.checkLoginLockout(ctx, attemptKeys); err != nil {
+		return err
+	}
+	var used bool
+	userQuery := queries.NewUser(ctx.Context())
+	totpService := totp.New()
+	step, ok, err := totpService.Validate(user.MfaSecret, code, time.Now())
+	if err != nil {
+		logger.Error().Err(err).Str("function", "checkMfaCode").Str("functionInline", "totpService.Validate").Msg("authenticateService")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if ok {
+		used, err = userQuery.UseMfaStepById(user.Id, step)
+	} else if user.IsMfaEnabled() {
+		used, err = userQuery.UseMfaRecoveryCodeById(user.Id, hashUserToken(totpService.NormalizeRecoveryCode(code)))
+	}
+	if err != nil {
+		return err
+	}
+	if !used {
+		return s.recordLoginFailure(ctx.Context(), attemptKeys, respErr.ErrMfaCodeWrong)
+	}
+	s.resetLoginFailures(ctx.Context(), attemptKeys[0])
+	return nil
+}
+
+// generateRecoveryCodes returns the codes to show the user once and the hashes to store.
+func (s *service) generateRecoveryCodes() ([]string, []string, error) {
+	codes, err := totp.New().GenerateRecoveryCodes(mfaRecoveryCodeCount)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "generateRecoveryCodes").Str("functionInline", "totp.New().GenerateRecoveryCodes").Msg("authenticateService")
+		return nil, nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashUserToken(totp.New().NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// resolveSsoUser finds the user behind a provider identity. A known identity logs into the user it
+// is linked to. Otherwise it is linked to the one user whose email is verified both here and at the
+// provider, or a new user is created.
+func (s *service) resolveSsoUser(ctx context.Context, provider string, identity sso.Identity) (*models.User, error) {
+	user, err := s.getSsoUser(ctx, provider, identity)
+	if e := new(response.Error); err == nil || !errors.As(err, &e) || e.Code != fiber.StatusNotFound {
+		return user, err
+	}
+	err = queries.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.linkSsoUser(ctx, provider, identity)
+		return err
+	})
+	// A concurrent callback for the same identity may have linked it first; its user is the answer.
+	if e := new(response.Error); err != nil && errors.As(err, &e) && e.Code == fiber.StatusConflict {
+		if linked, linkedErr := s.getSsoUser(ctx, provider, identity); linkedErr == nil {
+			return linked, nil
+		}
+	}
+	return user, err
+}
+
+// getSsoUser returns the user an identity is already linked to, or a 404 error.
+func (s *service) getSsoUser(ctx context.Context, provider string, identity sso.Identity) (*models.User, error) {
+	userIdentityQuery := queries.NewUserIdentity(ctx)
+	userIdentity, err := userIdentityQuery.GetByProviderAndSubject(provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if err = userIdentityQuery.TouchById(userIdentity.Id); err != nil {
+		return nil, err
+	}
+	userOption := queries.NewOptions()
+	userOption.SetOnlyFields("_id", "username", "email_verified_at", "mfa_enabled_at")
+	return queries.NewUser(ctx).GetById(userIdentity.UserId, userOption)
+}
+
+// linkSsoUser links a new identity to the user with its verified email as sso.Identity.LinkTarget
+// decides, creating the user when there is none. It runs in the transaction of resolveSsoUser, so a failed link never leaves a user behind.
+func (s *service) linkSsoUser(ctx context.Context, provider string, identity sso.Identity) (*models.User, error) {
+	userOption := queries.NewOptions()
+	userOption.SetOnlyFields("_id", "username", "email_verified_at", "mfa_enabled_at")
+	users, err := queries.NewUser(ctx).GetByEmail(identity.Email, userOption)
+	if err != nil {
+		return nil, err
+	}
+	emailVerified := make([]bool, len(users))
+	for i := range users {
+		emailVerified[i] = users[i].IsEmailVerified()
+	}
+	target, err := identity.LinkTarget(emailVerified)
+	switch {
+	case errors.Is(err, sso.ErrEmailNotVerified):
+		return nil, response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrSsoEmailNotVerified})
+	case errors.Is(err, sso.ErrAccountNotLinkable):
+		return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: respErr.ErrSsoAccountNotLinkable})
+	}
+	var user *models.User
+	if target >= 0 {
+		user = &users[target]
+	} else if user, err = s.createSsoUser(ctx, identity); err != nil {
+		return nil, err
+	}
+	if _, err = queries.NewUserIdentity(ctx).Create(models.UserIdentity{
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+		UserId:   user.Id,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createSsoUser registers the person behind identity, under the username they use at the provider
+// when it is free and with a random suffix otherwise. Usernames are checked before the insert since
+// a duplicate key would abort the surrounding transaction.
+func (s *service) createSsoUser(ctx context.Context, identity sso.Identity) (*models.User, error) {
+	username := ssoUsername(identity)
+	userQuery := queries.NewUser(ctx)
+	userOption := queries.NewOptions()
+	userOption.SetOnlyFields("_id")
+	for attempt := 0; attempt < ssoUsernameAttempts; attempt++ {
+		candidate := username
+		if attempt > 0 {
+			suffix := make([]byte, 3)
+			if _, err := rand.Read(suffix); err != nil {
+				logger.Error().Err(err).Str("function", "createSsoUser").Str("functionInline", "rand.Read").Msg("authenticateService")
+				return nil, response.NewError(fiber.StatusInternalServerError)
+			}
+			candidate += "-" + hex.EncodeToString(suffix)
+		}
+		_, err := userQuery.GetByUsername(candidate, userOption)
+		if err == nil {
+			continue
+		}
+		if e := new(response.Error); !errors.As(err, &e) || e.Code != fiber.StatusNotFound {
+			return nil, err
+		}
+		currentTime := time.Now()
+		return userQuery.Create(models.User{
+			EmailVerifiedAt: &currentTime,
+			Username:        candidate,
+			Email:           identity.Email,
+		})
+	}
+	return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "User already exists"})
+}
+
+// ssoUsername derives a username from the provider username or, failing that, the email, keeping
+// only characters that are safe in URLs and mentions.
+func ssoUsername(identity sso.Identity) string {
+	source := identity.Username
+	if source == "" {
+		source, _, _ = strings.Cut(identity.Email, "@")
+	}
+	username := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-' {
+			return r
+		}
+		return -1
+	}, source)
+	if len(username) > ssoUsernameMaxLength {
+		username = username[:ssoUsernameMaxLength]
+	}
+	if username == "" {
+		username = "user"
+	}
+	return username
+}
+
+// loginLockout is how long a key stays locked after its failures-th failure: nothing up to threshold,
+// then LoginLockoutBase doubled for every further failure, up to LoginLockoutMax.
+func loginLockout(failures, threshold int64) time.Duration {
+	if failures <= threshold {
+		return 0
+	}
+	lockout := cfg.LoginLockoutBase
+	for i := threshold + 1; i < failures && lockout < cfg.LoginLockoutMax; i++ {
+		lockout *= 2
+	}
+	return min(lockout, cfg.LoginLockoutMax)
+}
+
+func hashUserToken(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func userTokenLink(path, secret string) string {
+	return strings.TrimRight(cfg.WebUrl, "/") + path + "?token=" + url.QueryEscape(secret)
+}
+
+// readableDuration spells a link lifetime for an email, rounding to whole hours or minutes.
+func readableDuration(duration time.Duration) string {
+	if duration >= time.Hour {
+		if hours := int(duration.Round(time.Hour).Hours()); hours > 1 {
+			return fmt.Sprintf("%d hours", hours)
+		}
+		return "1 hour"
+	}
+	if minutes := int(duration.Round(time.Minute).Minutes()); minutes > 1 {
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+	return "1 minute"
+}
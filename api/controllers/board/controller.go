@@ -0,0 +1,147 @@
+package board
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/request"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/jobs"
+	"jira-clone-api/utilities/lexorank"
+	"jira-clone-api/utilities/local"
+)
+
+// boardIssueLimit caps how many cards a single board response loads.
+const boardIssueLimit = 2000
+
+type Controller interface {
+	Get(ctx *fiber.Ctx) error
+	Move(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Get(ctx *fiber.Ctx) error {
+	var requestQuery serializers.BoardQueryValidate
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	workflow, err := queries.NewWorkflow(ctx.Context()).GetByWorkspaceId(workspaceId)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{}
+	if requestQuery.ProjectId != "" {
+		filter["project_id"], _ = primitive.ObjectIDFromHex(requestQuery.ProjectId)
+	}
+	if requestQuery.AssigneeId != "" {
+		filter["assignee_id"], _ = primitive.ObjectIDFromHex(requestQuery.AssigneeId)
+	}
+	queryOption := queries.NewOptions()
+	queryOption.SetPagination(&request.Pagination{Limit: boardIssueLimit})
+	queryOption.SetOnlyFields("_id", "key", "title", "status", "priority", "rank", "labels", "project_id", "assignee_id")
+	queryOption.AddSortKey(map[string]int{"rank": queries.SortTypeAsc})
+	queryOption.AddSortKey(map[string]int{"_id": queries.SortTypeAsc})
+	issues, err := queries.NewIssue(ctx.Context()).GetByWorkspaceId(workspaceId, filter, queryOption)
+	if err != nil {
+		return err
+	}
+	columns := make([]serializers.BoardColumnResponse, len(workflow.Statuses))
+	columnIndex := make(map[string]int, len(workflow.Statuses))
+	for i, status := range workflow.Statuses {
+		columns[i] = serializers.BoardColumnResponse{
+			Status:   status.Name,
+			Category: status.Category,
+			Issues:   make([]serializers.BoardCardResponse, 0),
+		}
+		columnIndex[status.Name] = i
+	}
+	for i := 0; i < len(issues); i++ {
+		if index, ok := columnIndex[issues[i].Status]; ok {
+			columns[index].Issues = append(columns[index].Issues, ctrl.service.toCard(issues[i]))
+		}
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: columns,
+	})
+}
+
+func (ctrl *controller) Move(ctx *fiber.Ctx) error {
+	var requestBody serializers.BoardMoveBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	issueId, err := primitive.ObjectIDFromHex(ctx.Params("issueId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "status")
+	current, err := queries.NewIssue(ctx.Context()).GetByIdAndWorkspaceId(issueId, workspaceId, issueOption)
+	if err != nil {
+		return err
+	}
+	status := requestBody.Status
+	if status == "" {
+		status = current.Status
+	}
+	if status != current.Status {
+		workflow, err := queries.NewWorkflow(ctx.Context()).GetByWorkspaceId(workspaceId)
+		if err != nil {
+			return err
+		}
+		if err = workflow.CanTransition(current.Status, status); err != nil {
+			return queries.WorkflowError(err)
+		}
+	}
+	prevRank, err := ctrl.service.getNeighbourRank(ctx.Context(), workspaceId, issueId, requestBody.PrevId, status)
+	if err != nil {
+		return err
+	}
+	nextRank, err := ctrl.service.getNeighbourRank(ctx.Context(), workspaceId, issueId, requestBody.NextId, status)
+	if err != nil {
+		return err
+	}
+	if nextRank != "" && prevRank >= nextRank {
+		// Cards created at the same moment can share a rank; spread the column so the move can be retried.
+		jobs.RebalanceBoardColumn(workspaceId, status)
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Board column is being rebalanced, retry shortly"})
+	}
+	rankService := lexorank.New()
+	rank := rankService.Between(prevRank, nextRank)
+	issue, err := queries.NewIssue(ctx.Context()).MoveByIdAndWorkspaceId(issueId, workspaceId, current.Status, status, rank)
+	if err != nil {
+		return err
+	}
+	if rankService.NeedsRebalance(rank) {
+		jobs.RebalanceBoardColumn(workspaceId, status)
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toCard(*issue),
+	})
+}
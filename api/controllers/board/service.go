@@ -0,0 +1,63 @@
+package board
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/jobs"
+)
+
+type serviceInterface interface {
+	getNeighbourRank(ctx context.Context, workspaceId, issueId, neighbourId primitive.ObjectID, status string) (string, error)
+	toCard(issue models.Issue) serializers.BoardCardResponse
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// getNeighbourRank returns the rank of a card next to the drop position, or "" for the column edge.
+func (s *service) getNeighbourRank(ctx context.Context, workspaceId, issueId, neighbourId primitive.ObjectID, status string) (string, error) {
+	if neighbourId.IsZero() {
+		return "", nil
+	}
+	if neighbourId == issueId {
+		return "", response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Issue cannot be placed next to itself"})
+	}
+	queryOption := queries.NewOptions()
+	queryOption.SetOnlyFields("status", "rank")
+	neighbour, err := queries.NewIssue(ctx).GetByIdAndWorkspaceId(neighbourId, workspaceId, queryOption)
+	if err != nil {
+		return "", err
+	}
+	if neighbour.Status != status {
+		return "", response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Neighbouring issue is not in the target column"})
+	}
+	if neighbour.Rank == "" {
+		// Cards without a rank only exist until the column has been rebalanced.
+		jobs.RebalanceBoardColumn(workspaceId, status)
+		return "", response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Board column is being rebalanced, retry shortly"})
+	}
+	return neighbour.Rank, nil
+}
+
+func (s *service) toCard(issue models.Issue) serializers.BoardCardResponse {
+	return serializers.BoardCardResponse{
+		Key:        issue.Key,
+		Title:      issue.Title,
+		Status:     issue.Status,
+		Priority:   issue.Priority,
+		Rank:       issue.Rank,
+		Labels:     issue.Labels,
+		ProjectId:  issue.ProjectId,
+		AssigneeId: issue.AssigneeId,
+		Id:         issue.Id,
+	}
+}
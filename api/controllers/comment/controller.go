@@ -0,0 +1,198 @@
+package comment
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+)
+
+const commentDefaultLimit = 20
+
+type Controller interface {
+	Create(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Create(ctx *fiber.Ctx) error {
+	var requestBody serializers.CommentCreateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "project_id")
+	issue, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), workspaceId, issueOption)
+	if err != nil {
+		return err
+	}
+	if !requestBody.ParentId.IsZero() {
+		parentOption := queries.NewOptions()
+		parentOption.SetOnlyFields("_id", "parent_id")
+		parent, err := queries.NewComment(ctx.Context()).GetByIdAndIssueId(requestBody.ParentId, issue.Id, parentOption)
+		if err != nil {
+			return err
+		}
+		if parent.IsReply() {
+			return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Replies cannot be replied to"})
+		}
+	}
+	mentions, err := ctrl.service.resolveMentions(ctx.Context(), workspaceId, requestBody.Body)
+	if err != nil {
+		return err
+	}
+	comment, err := ctrl.service.create(ctx.Context(), models.Comment{
+		Body:        requestBody.Body,
+		Mentions:    mentions,
+		WorkspaceId: workspaceId,
+		ProjectId:   issue.ProjectId,
+		IssueId:     issue.Id,
+		ParentId:    requestBody.ParentId,
+		AuthorId:    localService.GetUser().Id,
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*comment),
+	})
+}
+
+func (ctrl *controller) List(ctx *fiber.Ctx) error {
+	var requestQuery serializers.CommentListQueryValidate
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "project_id")
+	issue, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), local.New(ctx).GetWorkspace().Id, issueOption)
+	if err != nil {
+		return err
+	}
+	limit := requestQuery.Limit
+	if limit == 0 {
+		limit = commentDefaultLimit
+	}
+	parentId, _ := primitive.ObjectIDFromHex(requestQuery.ParentId)
+	afterId, _ := primitive.ObjectIDFromHex(requestQuery.After)
+	// One extra comment tells whether another page exists.
+	comments, err := queries.NewComment(ctx.Context()).GetByIssueIdAndParentIdAfter(issue.Id, parentId, afterId, limit+1)
+	if err != nil {
+		return err
+	}
+	nextCursor := ""
+	if int64(len(comments)) > limit {
+		comments = comments[:limit]
+		nextCursor = comments[limit-1].Id.Hex()
+	}
+	results := make([]serializers.CommentResponse, len(comments))
+	for i := 0; i < len(comments); i++ {
+		results[i] = ctrl.service.toResponse(comments[i])
+	}
+	return response.New(ctx, response.Options{
+		Code:  fiber.StatusOK,
+		Data:  results,
+		Extra: fiber.Map{"limit": limit, "next_cursor": nextCursor},
+	})
+}
+
+func (ctrl *controller) Update(ctx *fiber.Ctx) error {
+	var requestBody serializers.CommentUpdateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	current, err := ctrl.getOwnComment(ctx, workspaceId, localService.GetUser().Id)
+	if err != nil {
+		return err
+	}
+	mentions, err := ctrl.service.resolveMentions(ctx.Context(), workspaceId, requestBody.Body)
+	if err != nil {
+		return err
+	}
+	comment, err := queries.NewComment(ctx.Context()).UpdateByIdAndAuthorId(current.Id, current.AuthorId, bson.M{
+		"body":      requestBody.Body,
+		"mentions":  mentions,
+		"edited_at": time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*comment),
+	})
+}
+
+func (ctrl *controller) Delete(ctx *fiber.Ctx) error {
+	localService := local.New(ctx)
+	comment, err := ctrl.getOwnComment(ctx, localService.GetWorkspace().Id, localService.GetUser().Id)
+	if err != nil {
+		return err
+	}
+	if err = ctrl.service.delete(ctx.Context(), comment); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+// getOwnComment loads the comment of the route and checks that userId wrote it.
+func (ctrl *controller) getOwnComment(ctx *fiber.Ctx, workspaceId, userId primitive.ObjectID) (*models.Comment, error) {
+	commentId, err := primitive.ObjectIDFromHex(ctx.Params("commentId"))
+	if err != nil {
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "project_id")
+	issue, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), workspaceId, issueOption)
+	if err != nil {
+		return nil, err
+	}
+	commentOption := queries.NewOptions()
+	commentOption.SetOnlyFields("_id", "parent_id", "author_id")
+	comment, err := queries.NewComment(ctx.Context()).GetByIdAndIssueId(commentId, issue.Id, commentOption)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorId != userId {
+		return nil, response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	return comment, nil
+}
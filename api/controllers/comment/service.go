@@ -0,0 +1,114 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+)
+
+// maxMentions bounds the user lookups a single comment can trigger.
+const maxMentions = 20
+
+var mentionRegex = regexp.MustCompile(`(?:^|[^A-Za-z0-9_])@([A-Za-z0-9_][A-Za-z0-9_.-]*)`)
+
+type serviceInterface interface {
+	create(ctx context.Context, comment models.Comment) (*models.Comment, error)
+	delete(ctx context.Context, comment *models.Comment) error
+	resolveMentions(ctx context.Context, workspaceId primitive.ObjectID, body string) ([]primitive.ObjectID, error)
+	toResponse(comment models.Comment) serializers.CommentResponse
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// create inserts the comment and, for a reply, counts it on its parent in one transaction. Counting
+// fails when the parent was deleted meanwhile, so no reply outlives its parent.
+func (s *service) create(ctx context.Context, comment models.Comment) (*models.Comment, error) {
+	var newComment *models.Comment
+	err := queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if comment.IsReply() {
+			if err := queries.NewComment(ctx).IncreaseReplyCountById(comment.ParentId, 1); err != nil {
+				return err
+			}
+		}
+		var err error
+		newComment, err = queries.NewComment(ctx).Create(comment)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newComment, nil
+}
+
+// delete removes the comment together with its replies, or a reply together with its count on the
+// parent, in one transaction.
+func (s *service) delete(ctx context.Context, comment *models.Comment) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := queries.NewComment(ctx).DeleteById(comment.Id); err != nil {
+			return err
+		}
+		if comment.IsReply() {
+			return queries.NewComment(ctx).IncreaseReplyCountById(comment.ParentId, -1)
+		}
+		return queries.NewComment(ctx).DeleteByParentId(comment.Id)
+	})
+}
+
+// resolveMentions returns the ids of the workspace members mentioned as @username in body.
+// Unknown usernames and users outside the workspace are ignored.
+func (s *service) resolveMentions(ctx context.Context, workspaceId primitive.ObjectID, body string) ([]primitive.ObjectID, error) {
+	mentions := make([]primitive.ObjectID, 0)
+	seen := make(map[string]struct{})
+	userOption := queries.NewOptions()
+	userOption.SetOnlyFields("_id")
+	for _, match := range mentionRegex.FindAllStringSubmatch(body, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if _, ok := seen[username]; ok {
+			continue
+		}
+		if len(seen) == maxMentions {
+			break
+		}
+		seen[username] = struct{}{}
+		user, err := queries.NewUser(ctx).GetByUsername(username, userOption)
+		if err != nil {
+			if e := new(response.Error); errors.As(err, &e) && e.Code < 500 {
+				continue
+			}
+			return nil, err
+		}
+		isMember, err := queries.NewWorkspaceMember(ctx).IsMember(workspaceId, user.Id)
+		if err != nil {
+			return nil, err
+		}
+		if isMember {
+			mentions = append(mentions, user.Id)
+		}
+	}
+	return mentions, nil
+}
+
+func (s *service) toResponse(comment models.Comment) serializers.CommentResponse {
+	return serializers.CommentResponse{
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+		EditedAt:   comment.EditedAt,
+		Body:       comment.Body,
+		Mentions:   comment.Mentions,
+		ReplyCount: comment.ReplyCount,
+		ParentId:   comment.ParentId,
+		AuthorId:   comment.AuthorId,
+		Id:         comment.Id,
+	}
+}
@@ -0,0 +1,355 @@
+package issue
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/request"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+)
+
+// issueListFields is what the filter and sort query parameters of Search may refer to.
+var issueListFields = queries.ListFields{
+	"key":         {Type: queries.FieldTypeString, Filterable: true},
+	"number":      {Type: queries.FieldTypeNumber, Filterable: true, Sortable: true},
+	"title":       {Type: queries.FieldTypeString, Filterable: true, Sortable: true, Search: true},
+	"status":      {Type: queries.FieldTypeString, Filterable: true, Sortable: true},
+	"priority":    {Type: queries.FieldTypeString, Filterable: true},
+	"labels":      {Type: queries.FieldTypeString, Filterable: true},
+	"project_id":  {Type: queries.FieldTypeObjectId, Filterable: true},
+	"assignee_id": {Type: queries.FieldTypeObjectId, Filterable: true},
+	"reporter_id": {Type: queries.FieldTypeObjectId, Filterable: true},
+	"sprint_id":   {Type: queries.FieldTypeObjectId, Filterable: true},
+	"due_date":    {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+	"created_at":  {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+	"updated_at":  {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+}
+
+type Controller interface {
+	Create(ctx *fiber.Ctx) error
+	Search(ctx *fiber.Ctx) error
+	SearchJQL(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Create(ctx *fiber.Ctx) error {
+	var requestBody serializers.IssueCreateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	projectOption := queries.NewOptions()
+	projectOption.SetOnlyFields("_id", "key", "workspace_id")
+	project, err := queries.NewProject(ctx.Context()).GetByIdAndWorkspaceId(requestBody.ProjectId, workspaceId, projectOption)
+	if err != nil {
+		return err
+	}
+	if !requestBody.AssigneeId.IsZero() {
+		if err = ctrl.service.ensureAssignable(ctx.Context(), workspaceId, requestBody.AssigneeId); err != nil {
+			return err
+		}
+	}
+	workflow, err := queries.NewWorkflow(ctx.Context()).GetByWorkspaceId(workspaceId)
+	if err != nil {
+		return err
+	}
+	status := requestBody.Status
+	if status == "" {
+		status = workflow.InitialStatus
+	}
+	if err = ctrl.service.ensureStatus(workflow, status); err != nil {
+		return err
+	}
+	priority := requestBody.Priority
+	if priority == "" {
+		priority = constants.IssuePriorityMedium
+	}
+	issue, err := ctrl.service.create(ctx.Context(), project, models.Issue{
+		DueDate:     requestBody.DueDate,
+		Title:       requestBody.Title,
+		Description: requestBody.Description,
+		Status:      status,
+		Priority:    priority,
+		Labels:      requestBody.Labels,
+		WorkspaceId: workspaceId,
+		ProjectId:   project.Id,
+		AssigneeId:  requestBody.AssigneeId,
+		ReporterId:  localService.GetUser().Id,
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: fiber.Map{
+			"id":  issue.Id,
+			"key": issue.Key,
+		},
+	})
+}
+
+func (ctrl *controller) Search(ctx *fiber.Ctx) error {
+	var (
+		requestQuery serializers.IssueSearchQueryValidate
+		totalChan    = make(chan int64, 1)
+		errChan      = make(chan error, 1)
+	)
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	filter := bson.M{}
+	if requestQuery.Title != "" {
+		filter = queries.NewSearchFilter("title", queries.QueryMethodContains, requestQuery.Title)
+	}
+	if requestQuery.Status != "" {
+		filter["status"] = requestQuery.Status
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	if requestQuery.Category != "" && requestQuery.Status == "" {
+		workflow, err := queries.NewWorkflow(ctx.Context()).GetByWorkspaceId(workspaceId)
+		if err != nil {
+			return err
+		}
+		filter["status"] = bson.M{"$in": workflow.StatusesInCategory(requestQuery.Category)}
+	}
+	if requestQuery.Priority != "" {
+		filter["priority"] = requestQuery.Priority
+	}
+	if requestQuery.ProjectId != "" {
+		filter["project_id"], _ = primitive.ObjectIDFromHex(requestQuery.ProjectId)
+	}
+	if requestQuery.AssigneeId != "" {
+		filter["assignee_id"], _ = primitive.ObjectIDFromHex(requestQuery.AssigneeId)
+	}
+	if requestQuery.SprintId != "" {
+		filter["sprint_id"], _ = primitive.ObjectIDFromHex(requestQuery.SprintId)
+	} else if requestQuery.Backlog {
+		filter["sprint_id"] = bson.M{"$exists": false}
+	}
+	if err := issueListFields.BuildFilter(filter, requestQuery.Filter); err != nil {
+		return err
+	}
+	sort, err := issueListFields.ParseSort(requestQuery.Sort)
+	if err != nil {
+		return err
+	}
+	totalFilter := bson.M{}
+	for key, value := range filter {
+		totalFilter[key] = value
+	}
+	go func() {
+		total, err := queries.NewIssue(ctx.Context()).TotalByWorkspaceId(workspaceId, totalFilter)
+		errChan <- err
+		totalChan <- total
+	}()
+	pagination := request.NewPagination(requestQuery.Limit, requestQuery.Page)
+	queryOption := queries.NewOptions()
+	queryOption.SetPagination(pagination)
+	queryOption.AddSortKeys(sort)
+	queryOption.AddSortKey(map[string]int{"_id": -1})
+	issues, err := queries.NewIssue(ctx.Context()).GetByWorkspaceId(workspaceId, filter, queryOption)
+	if err != nil {
+		return err
+	}
+	if err = <-errChan; err != nil {
+		return err
+	}
+	pagination.SetTotal(<-totalChan)
+	results := make([]serializers.IssueResponse, len(issues))
+	for i := 0; i < len(issues); i++ {
+		results[i] = ctrl.service.toResponse(issues[i])
+	}
+	return response.NewArrayWithPagination(ctx, results, pagination)
+}
+
+// SearchJQL lists the issues matching a JQL query or one of the caller's saved filters.
+func (ctrl *controller) SearchJQL(ctx *fiber.Ctx) error {
+	var (
+		requestBody serializers.IssueJQLSearchBodyValidate
+		totalChan   = make(chan int64, 1)
+		errChan     = make(chan error, 1)
+	)
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	userId := localService.GetUser().Id
+	input := requestBody.JQL
+	if requestBody.FilterId != "" {
+		filterId, _ := primitive.ObjectIDFromHex(requestBody.FilterId)
+		savedFilter, err := queries.NewSavedFilter(ctx.Context()).GetByIdAndUserIdAndWorkspaceId(filterId, userId, workspaceId)
+		if err != nil {
+			return err
+		}
+		input = savedFilter.JQL
+	}
+	filter, sort, err := queries.CompileIssueJQL(ctx.Context(), workspaceId, userId, input)
+	if err != nil {
+		return err
+	}
+	totalFilter := bson.M{}
+	for key, value := range filter {
+		totalFilter[key] = value
+	}
+	go func() {
+		total, err := queries.NewIssue(ctx.Context()).TotalByWorkspaceId(workspaceId, totalFilter)
+		errChan <- err
+		totalChan <- total
+	}()
+	pagination := request.NewPagination(requestBody.Limit, requestBody.Page)
+	queryOption := queries.NewOptions()
+	queryOption.SetPagination(pagination)
+	queryOption.AddSortKeys(sort)
+	queryOption.AddSortKey(map[string]int{"_id": -1})
+	issues, err := queries.NewIssue(ctx.Context()).GetByWorkspaceId(workspaceId, filter, queryOption)
+	if err != nil {
+		return err
+	}
+	if err = <-errChan; err != nil {
+		return err
+	}
+	pagination.SetTotal(<-totalChan)
+	results := make([]serializers.IssueResponse, len(issues))
+	for i := 0; i < len(issues); i++ {
+		results[i] = ctrl.service.toResponse(issues[i])
+	}
+	return response.NewArrayWithPagination(ctx, results, pagination)
+}
+
+func (ctrl *controller) Get(ctx *fiber.Ctx) error {
+	issue, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), local.New(ctx).GetWorkspace().Id)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*issue),
+	})
+}
+
+func (ctrl *controller) Update(ctx *fiber.Ctx) error {
+	var requestBody serializers.IssueUpdateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "status")
+	current, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), workspaceId, issueOption)
+	if err != nil {
+		return err
+	}
+	data := bson.M{}
+	if requestBody.Title != nil {
+		data["title"] = *requestBody.Title
+	}
+	if requestBody.Description != nil {
+		data["description"] = *requestBody.Description
+	}
+	if requestBody.Status != nil {
+		workflow, err := queries.NewWorkflow(ctx.Context()).GetByWorkspaceId(workspaceId)
+		if err != nil {
+			return err
+		}
+		if err = workflow.CanTransition(current.Status, *requestBody.Status); err != nil {
+			return queries.WorkflowError(err)
+		}
+		if *requestBody.Status != current.Status {
+			if data["rank"], err = ctrl.service.bottomRank(ctx.Context(), workspaceId, *requestBody.Status); err != nil {
+				return err
+			}
+		}
+		data["status"] = *requestBody.Status
+	}
+	if requestBody.Priority != nil {
+		data["priority"] = *requestBody.Priority
+	}
+	if requestBody.Labels != nil {
+		data["labels"] = *requestBody.Labels
+	}
+	if requestBody.DueDate != nil {
+		data["due_date"] = *requestBody.DueDate
+	}
+	if requestBody.AssigneeId != nil {
+		// A zero id unassigns the issue.
+		if !requestBody.AssigneeId.IsZero() {
+			if err = ctrl.service.ensureAssignable(ctx.Context(), workspaceId, *requestBody.AssigneeId); err != nil {
+				return err
+			}
+		}
+		data["assignee_id"] = *requestBody.AssigneeId
+	}
+	if len(data) == 0 {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrValueIsNotAccepted,
+		})
+	}
+	var issue *models.Issue
+	if requestBody.Status != nil {
+		// The transition was checked against current.Status, so it only holds while the issue is still there.
+		issue, err = queries.NewIssue(ctx.Context()).UpdateByIdAndWorkspaceIdAndStatus(current.Id, workspaceId, current.Status, data)
+	} else {
+		issue, err = queries.NewIssue(ctx.Context()).UpdateByIdAndWorkspaceId(current.Id, workspaceId, data)
+	}
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*issue),
+	})
+}
+
+func (ctrl *controller) Delete(ctx *fiber.Ctx) error {
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id")
+	issue, err := queries.NewIssue(ctx.Context()).GetByIdOrKeyAndWorkspaceId(ctx.Params("issueId"), workspaceId, issueOption)
+	if err != nil {
+		return err
+	}
+	if err = ctrl.service.delete(ctx.Context(), workspaceId, issue.Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
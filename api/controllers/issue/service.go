@@ -0,0 +1,117 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/jobs"
+	"jira-clone-api/utilities/lexorank"
+)
+
+type serviceInterface interface {
+	create(ctx context.Context, project *models.Project, issue models.Issue) (*models.Issue, error)
+	delete(ctx context.Context, workspaceId, issueId primitive.ObjectID) error
+	bottomRank(ctx context.Context, workspaceId primitive.ObjectID, status string) (string, error)
+	ensureAssignable(ctx context.Context, workspaceId, userId primitive.ObjectID) error
+	ensureStatus(workflow *models.Workflow, status string) error
+	toResponse(issue models.Issue) serializers.IssueResponse
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// create allocates the next number of the project and inserts the issue in one transaction,
+// so concurrent creates never share a number and a failed insert never leaves a gap.
+func (s *service) create(ctx context.Context, project *models.Project, issue models.Issue) (*models.Issue, error) {
+	var newIssue *models.Issue
+	err := queries.WithTransaction(ctx, func(ctx context.Context) error {
+		number, err := queries.NewCounter(ctx).Next(project.Id, project.WorkspaceId)
+		if err != nil {
+			return err
+		}
+		issue.Number = number
+		issue.Key = fmt.Sprintf("%s-%d", project.Key, number)
+		if issue.Rank, err = s.bottomRank(ctx, issue.WorkspaceId, issue.Status); err != nil {
+			return err
+		}
+		newIssue, err = queries.NewIssue(ctx).Create(issue)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newIssue, nil
+}
+
+// bottomRank returns a rank that places an issue at the bottom of its board column.
+// delete removes the issue with its comments and expires its attachments in one transaction,
+// so a failure never leaves orphans behind.
+func (s *service) delete(ctx context.Context, workspaceId, issueId primitive.ObjectID) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := queries.NewIssue(ctx).DeleteByIdAndWorkspaceId(issueId, workspaceId); err != nil {
+			return err
+		}
+		if err := queries.NewComment(ctx).DeleteByIssueId(issueId); err != nil {
+			return err
+		}
+		return queries.NewAttachment(ctx).ExpireByIssueId(issueId)
+	})
+}
+
+func (s *service) bottomRank(ctx context.Context, workspaceId primitive.ObjectID, status string) (string, error) {
+	lastRank, err := queries.NewIssue(ctx).GetLastRankByWorkspaceIdAndStatus(workspaceId, status)
+	if err != nil {
+		return "", err
+	}
+	rank := lexorank.New().Between(lastRank, "")
+	if lexorank.New().NeedsRebalance(rank) {
+		jobs.RebalanceBoardColumn(workspaceId, status)
+	}
+	return rank, nil
+}
+
+func (s *service) ensureAssignable(ctx context.Context, workspaceId, userId primitive.ObjectID) error {
+	isMember, err := queries.NewWorkspaceMember(ctx).IsMember(workspaceId, userId)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Assignee must be a workspace member"})
+	}
+	return nil
+}
+
+func (s *service) ensureStatus(workflow *models.Workflow, status string) error {
+	if !workflow.HasStatus(status) {
+		return queries.WorkflowError(models.ErrWorkflowStatusUnknown)
+	}
+	return nil
+}
+
+func (s *service) toResponse(issue models.Issue) serializers.IssueResponse {
+	return serializers.IssueResponse{
+		CreatedAt:   issue.CreatedAt,
+		UpdatedAt:   issue.UpdatedAt,
+		DueDate:     issue.DueDate,
+		Key:         issue.Key,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Status:      issue.Status,
+		Priority:    issue.Priority,
+		Labels:      issue.Labels,
+		ProjectId:   issue.ProjectId,
+		AssigneeId:  issue.AssigneeId,
+		SprintId:    issue.SprintId,
+		ReporterId:  issue.ReporterId,
+		Id:          issue.Id,
+	}
+}
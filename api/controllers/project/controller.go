@@ -0,0 +1,205 @@
+package project
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/request"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+)
+
+// projectListFields is what the filter and sort query parameters of Search may refer to.
+var projectListFields = queries.ListFields{
+	"name":       {Type: queries.FieldTypeString, Filterable: true, Sortable: true, Search: true},
+	"key":        {Type: queries.FieldTypeString, Filterable: true, Sortable: true},
+	"lead_id":    {Type: queries.FieldTypeObjectId, Filterable: true},
+	"created_at": {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+	"updated_at": {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+}
+
+type Controller interface {
+	Create(ctx *fiber.Ctx) error
+	Search(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Create(ctx *fiber.Ctx) error {
+	var requestBody serializers.ProjectCreateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	leadId := requestBody.LeadId
+	if leadId.IsZero() {
+		leadId = localService.GetUser().Id
+	} else if err := ctrl.service.ensureMember(ctx.Context(), workspaceId, leadId); err != nil {
+		return err
+	}
+	project, err := queries.NewProject(ctx.Context()).Create(models.Project{
+		Name:        requestBody.Name,
+		Key:         requestBody.Key,
+		Description: requestBody.Description,
+		Icon:        requestBody.Icon,
+		WorkspaceId: workspaceId,
+		LeadId:      leadId,
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: fiber.Map{
+			"id": project.Id,
+		},
+	})
+}
+
+func (ctrl *controller) Search(ctx *fiber.Ctx) error {
+	var (
+		requestQuery serializers.ProjectSearchQueryValidate
+		totalChan    = make(chan int64, 1)
+		errChan      = make(chan error, 1)
+	)
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	filter := bson.M{}
+	if requestQuery.Name != "" {
+		filter = queries.NewSearchFilter("name", queries.QueryMethodContains, requestQuery.Name)
+	}
+	if err := projectListFields.BuildFilter(filter, requestQuery.Filter); err != nil {
+		return err
+	}
+	sort, err := projectListFields.ParseSort(requestQuery.Sort)
+	if err != nil {
+		return err
+	}
+	totalFilter := bson.M{}
+	for key, value := range filter {
+		totalFilter[key] = value
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	go func() {
+		total, err := queries.NewProject(ctx.Context()).TotalByWorkspaceId(workspaceId, totalFilter)
+		errChan <- err
+		totalChan <- total
+	}()
+	pagination := request.NewPagination(requestQuery.Limit, requestQuery.Page)
+	queryOption := queries.NewOptions()
+	queryOption.SetPagination(pagination)
+	queryOption.AddSortKeys(sort)
+	queryOption.AddSortKey(map[string]int{"_id": -1})
+	projects, err := queries.NewProject(ctx.Context()).GetByWorkspaceId(workspaceId, filter, queryOption)
+	if err != nil {
+		return err
+	}
+	if err = <-errChan; err != nil {
+		return err
+	}
+	pagination.SetTotal(<-totalChan)
+	results := make([]serializers.ProjectResponse, len(projects))
+	for i := 0; i < len(projects); i++ {
+		results[i] = ctrl.service.toResponse(projects[i])
+	}
+	return response.NewArrayWithPagination(ctx, results, pagination)
+}
+
+func (ctrl *controller) Get(ctx *fiber.Ctx) error {
+	projectId, err := primitive.ObjectIDFromHex(ctx.Params("projectId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	project, err := queries.NewProject(ctx.Context()).GetByIdAndWorkspaceId(projectId, local.New(ctx).GetWorkspace().Id)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*project),
+	})
+}
+
+func (ctrl *controller) Update(ctx *fiber.Ctx) error {
+	projectId, err := primitive.ObjectIDFromHex(ctx.Params("projectId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	var requestBody serializers.ProjectUpdateBodyValidate
+	if err = ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err = requestBody.Validate(); err != nil {
+		return err
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	data := bson.M{}
+	if requestBody.Name != nil {
+		data["name"] = *requestBody.Name
+	}
+	if requestBody.Description != nil {
+		data["description"] = *requestBody.Description
+	}
+	if requestBody.Icon != nil {
+		data["icon"] = *requestBody.Icon
+	}
+	if requestBody.LeadId != nil {
+		if err = ctrl.service.ensureMember(ctx.Context(), workspaceId, *requestBody.LeadId); err != nil {
+			return err
+		}
+		data["lead_id"] = *requestBody.LeadId
+	}
+	if len(data) == 0 {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrValueIsNotAccepted,
+		})
+	}
+	project, err := queries.NewProject(ctx.Context()).UpdateByIdAndWorkspaceId(projectId, workspaceId, data)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*project),
+	})
+}
+
+func (ctrl *controller) Delete(ctx *fiber.Ctx) error {
+	projectId, err := primitive.ObjectIDFromHex(ctx.Params("projectId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	if err = ctrl.service.delete(ctx.Context(), local.New(ctx).GetWorkspace().Id, projectId); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
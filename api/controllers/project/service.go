@@ -0,0 +1,72 @@
+package project
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+)
+
+type serviceInterface interface {
+	ensureMember(ctx context.Context, workspaceId, userId primitive.ObjectID) error
+	delete(ctx context.Context, workspaceId, projectId primitive.ObjectID) error
+	toResponse(project models.Project) serializers.ProjectResponse
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// ensureMember checks that the user, e.g. a project lead, belongs to the workspace.
+func (s *service) ensureMember(ctx context.Context, workspaceId, userId primitive.ObjectID) error {
+	isMember, err := queries.NewWorkspaceMember(ctx).IsMember(workspaceId, userId)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Lead must be a workspace member"})
+	}
+	return nil
+}
+
+// delete removes the project with its issues, comments, counter and sprints, and expires its attachments,
+// in one transaction so a failure never leaves orphans behind.
+func (s *service) delete(ctx context.Context, workspaceId, projectId primitive.ObjectID) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := queries.NewProject(ctx).DeleteByIdAndWorkspaceId(projectId, workspaceId); err != nil {
+			return err
+		}
+		if err := queries.NewComment(ctx).DeleteByProjectId(projectId); err != nil {
+			return err
+		}
+		if err := queries.NewAttachment(ctx).ExpireByProjectId(projectId); err != nil {
+			return err
+		}
+		if err := queries.NewIssue(ctx).DeleteByProjectId(projectId); err != nil {
+			return err
+		}
+		if err := queries.NewCounter(ctx).DeleteById(projectId); err != nil {
+			return err
+		}
+		return queries.NewSprint(ctx).DeleteByProjectId(projectId)
+	})
+}
+
+func (s *service) toResponse(project models.Project) serializers.ProjectResponse {
+	return serializers.ProjectResponse{
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+		Name:        project.Name,
+		Key:         project.Key,
+		Description: project.Description,
+		Icon:        project.Icon,
+		LeadId:      project.LeadId,
+		Id:          project.Id,
+	}
+}
@@ -0,0 +1,175 @@
+package saved_filter
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/request"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+)
+
+// Controller manages the caller's own saved JQL filters; they are never visible to other members.
+type Controller interface {
+	Create(ctx *fiber.Ctx) error
+	Search(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Create(ctx *fiber.Ctx) error {
+	var requestBody serializers.SavedFilterCreateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	userId := localService.GetUser().Id
+	if _, _, err := queries.CompileIssueJQL(ctx.Context(), workspaceId, userId, requestBody.JQL); err != nil {
+		return err
+	}
+	savedFilter, err := queries.NewSavedFilter(ctx.Context()).Create(models.SavedFilter{
+		Name:        requestBody.Name,
+		JQL:         requestBody.JQL,
+		WorkspaceId: workspaceId,
+		UserId:      userId,
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*savedFilter),
+	})
+}
+
+func (ctrl *controller) Search(ctx *fiber.Ctx) error {
+	var (
+		requestQuery serializers.SavedFilterSearchQueryValidate
+		totalChan    = make(chan int64, 1)
+		errChan      = make(chan error, 1)
+	)
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	userId := localService.GetUser().Id
+	go func() {
+		total, err := queries.NewSavedFilter(ctx.Context()).TotalByUserIdAndWorkspaceId(userId, workspaceId)
+		errChan <- err
+		totalChan <- total
+	}()
+	pagination := request.NewPagination(requestQuery.Limit, requestQuery.Page)
+	queryOption := queries.NewOptions()
+	queryOption.SetPagination(pagination)
+	queryOption.AddSortKey(map[string]int{"name": queries.SortTypeAsc})
+	savedFilters, err := queries.NewSavedFilter(ctx.Context()).GetByUserIdAndWorkspaceId(userId, workspaceId, queryOption)
+	if err != nil {
+		return err
+	}
+	if err = <-errChan; err != nil {
+		return err
+	}
+	pagination.SetTotal(<-totalChan)
+	results := make([]serializers.SavedFilterResponse, len(savedFilters))
+	for i := 0; i < len(savedFilters); i++ {
+		results[i] = ctrl.service.toResponse(savedFilters[i])
+	}
+	return response.NewArrayWithPagination(ctx, results, pagination)
+}
+
+func (ctrl *controller) Get(ctx *fiber.Ctx) error {
+	filterId, err := primitive.ObjectIDFromHex(ctx.Params("filterId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	localService := local.New(ctx)
+	savedFilter, err := queries.NewSavedFilter(ctx.Context()).GetByIdAndUserIdAndWorkspaceId(filterId, localService.GetUser().Id, localService.GetWorkspace().Id)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*savedFilter),
+	})
+}
+
+func (ctrl *controller) Update(ctx *fiber.Ctx) error {
+	filterId, err := primitive.ObjectIDFromHex(ctx.Params("filterId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	var requestBody serializers.SavedFilterUpdateBodyValidate
+	if err = ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err = requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	userId := localService.GetUser().Id
+	data := bson.M{}
+	if requestBody.Name != "" {
+		data["name"] = requestBody.Name
+	}
+	if requestBody.JQL != "" {
+		if _, _, err = queries.CompileIssueJQL(ctx.Context(), workspaceId, userId, requestBody.JQL); err != nil {
+			return err
+		}
+		data["jql"] = requestBody.JQL
+	}
+	if len(data) == 0 {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrValueIsNotAccepted,
+		})
+	}
+	savedFilter, err := queries.NewSavedFilter(ctx.Context()).UpdateByIdAndUserIdAndWorkspaceId(filterId, userId, workspaceId, data)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*savedFilter),
+	})
+}
+
+func (ctrl *controller) Delete(ctx *fiber.Ctx) error {
+	filterId, err := primitive.ObjectIDFromHex(ctx.Params("filterId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	localService := local.New(ctx)
+	if err = queries.NewSavedFilter(ctx.Context()).DeleteByIdAndUserIdAndWorkspaceId(filterId, localService.GetUser().Id, localService.GetWorkspace().Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
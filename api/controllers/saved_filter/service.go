@@ -0,0 +1,26 @@
+package saved_filter
+
+import (
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/database/mongo/models"
+)
+
+type serviceInterface interface {
+	toResponse(savedFilter models.SavedFilter) serializers.SavedFilterResponse
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+func (s *service) toResponse(savedFilter models.SavedFilter) serializers.SavedFilterResponse {
+	return serializers.SavedFilterResponse{
+		CreatedAt: savedFilter.CreatedAt,
+		UpdatedAt: savedFilter.UpdatedAt,
+		Name:      savedFilter.Name,
+		JQL:       savedFilter.JQL,
+		Id:        savedFilter.Id,
+	}
+}
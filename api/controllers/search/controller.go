@@ -0,0 +1,116 @@
+package search
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/textsearch"
+)
+
+const searchDefaultLimit = 20
+
+var searchTypes = []string{constants.SearchTypeWorkspace, constants.SearchTypeProject, constants.SearchTypeIssue, constants.SearchTypeComment}
+
+// Controller searches everything the caller can see across the workspaces they belong to.
+type Controller interface {
+	Search(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+// Search ranks hits of every type by their text score. Scores come from different indexes, so the
+// merged order is a heuristic; within one type it is exact.
+func (ctrl *controller) Search(ctx *fiber.Ctx) error {
+	var requestQuery serializers.SearchQueryValidate
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	if requestQuery.Limit == 0 {
+		requestQuery.Limit = searchDefaultLimit
+	}
+	if len(requestQuery.Types) == 0 {
+		requestQuery.Types = searchTypes
+	}
+	results := make([]serializers.SearchResponseItem, 0)
+	terms := textsearch.New().Terms(requestQuery.Query)
+	if len(terms) == 0 {
+		return response.New(ctx, response.Options{Code: fiber.StatusOK, Data: results})
+	}
+	workspaceIds, err := ctrl.service.workspaceIds(ctx, requestQuery.WorkspaceId)
+	if err != nil {
+		return err
+	}
+	if len(workspaceIds) == 0 {
+		return response.New(ctx, response.Options{Code: fiber.StatusOK, Data: results})
+	}
+	search := textsearch.New().MongoSearch(terms)
+	if slices.Contains(requestQuery.Types, constants.SearchTypeWorkspace) {
+		workspaces, err := queries.NewWorkspace(ctx.Context()).SearchText(search, workspaceIds, requestQuery.Limit)
+		if err != nil {
+			return err
+		}
+		for _, workspace := range workspaces {
+			results = append(results, ctrl.service.workspaceToResponse(workspace, terms))
+		}
+	}
+	if slices.Contains(requestQuery.Types, constants.SearchTypeProject) {
+		projects, err := queries.NewProject(ctx.Context()).SearchText(search, workspaceIds, requestQuery.Limit)
+		if err != nil {
+			return err
+		}
+		for _, project := range projects {
+			results = append(results, ctrl.service.projectToResponse(project, terms))
+		}
+	}
+	if slices.Contains(requestQuery.Types, constants.SearchTypeIssue) {
+		issues, err := queries.NewIssue(ctx.Context()).SearchText(search, workspaceIds, requestQuery.Limit)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			results = append(results, ctrl.service.issueToResponse(issue, terms))
+		}
+	}
+	if slices.Contains(requestQuery.Types, constants.SearchTypeComment) {
+		comments, err := queries.NewComment(ctx.Context()).SearchText(search, workspaceIds, requestQuery.Limit)
+		if err != nil {
+			return err
+		}
+		items, err := ctrl.service.commentsToResponse(ctx, comments, terms)
+		if err != nil {
+			return err
+		}
+		results = append(results, items...)
+	}
+	slices.SortStableFunc(results, func(a, b serializers.SearchResponseItem) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	if int64(len(results)) > requestQuery.Limit {
+		results = results[:requestQuery.Limit]
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK, Data: results})
+}
@@ -0,0 +1,148 @@
+package search
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+	"jira-clone-api/utilities/textsearch"
+)
+
+type serviceInterface interface {
+	workspaceIds(ctx *fiber.Ctx, workspaceId string) ([]primitive.ObjectID, error)
+	workspaceToResponse(workspace queries.TextScored[models.Workspace], terms []string) serializers.SearchResponseItem
+	projectToResponse(project queries.TextScored[models.Project], terms []string) serializers.SearchResponseItem
+	issueToResponse(issue queries.TextScored[models.Issue], terms []string) serializers.SearchResponseItem
+	commentsToResponse(ctx *fiber.Ctx, comments []queries.TextScored[models.Comment], terms []string) ([]serializers.SearchResponseItem, error)
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// workspaceIds returns the live workspaces of the caller, or only workspaceId when it is given and
+// the caller is a member of it.
+func (s *service) workspaceIds(ctx *fiber.Ctx, workspaceId string) ([]primitive.ObjectID, error) {
+	memberOption := queries.NewOptions()
+	memberOption.SetOnlyFields("workspace_id")
+	members, err := queries.NewWorkspaceMember(ctx.Context()).GetByUserId(local.New(ctx).GetUser().Id, memberOption)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(members))
+	for _, member := range members {
+		if workspaceId == "" || member.WorkspaceId.Hex() == workspaceId {
+			ids = append(ids, member.WorkspaceId)
+		}
+	}
+	if workspaceId != "" && len(ids) == 0 {
+		return nil, response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	workspaceOption := queries.NewOptions()
+	workspaceOption.SetOnlyFields("_id")
+	workspaces, err := queries.NewWorkspace(ctx.Context()).GetByIds(ids, workspaceOption)
+	if err != nil {
+		return nil, err
+	}
+	ids = ids[:0]
+	for _, workspace := range workspaces {
+		ids = append(ids, workspace.Id)
+	}
+	return ids, nil
+}
+
+func (s *service) workspaceToResponse(workspace queries.TextScored[models.Workspace], terms []string) serializers.SearchResponseItem {
+	return serializers.SearchResponseItem{
+		Type:        constants.SearchTypeWorkspace,
+		Score:       workspace.Score,
+		Title:       workspace.Document.Name,
+		Snippet:     textsearch.New().Snippet(workspace.Document.Name, terms),
+		WorkspaceId: workspace.Document.Id,
+		Id:          workspace.Document.Id,
+	}
+}
+
+func (s *service) projectToResponse(project queries.TextScored[models.Project], terms []string) serializers.SearchResponseItem {
+	return serializers.SearchResponseItem{
+		Type:        constants.SearchTypeProject,
+		Score:       project.Score,
+		Title:       project.Document.Name,
+		Snippet:     s.snippet(project.Document.Name, project.Document.Description, terms),
+		Key:         project.Document.Key,
+		WorkspaceId: project.Document.WorkspaceId,
+		ProjectId:   &project.Document.Id,
+		Id:          project.Document.Id,
+	}
+}
+
+func (s *service) issueToResponse(issue queries.TextScored[models.Issue], terms []string) serializers.SearchResponseItem {
+	return serializers.SearchResponseItem{
+		Type:        constants.SearchTypeIssue,
+		Score:       issue.Score,
+		Title:       issue.Document.Title,
+		Snippet:     s.snippet(issue.Document.Title, issue.Document.Description, terms),
+		Key:         issue.Document.Key,
+		WorkspaceId: issue.Document.WorkspaceId,
+		ProjectId:   &issue.Document.ProjectId,
+		IssueId:     &issue.Document.Id,
+		Id:          issue.Document.Id,
+	}
+}
+
+// commentsToResponse titles every comment with its issue, which also drops comments whose issue is gone.
+func (s *service) commentsToResponse(ctx *fiber.Ctx, comments []queries.TextScored[models.Comment], terms []string) ([]serializers.SearchResponseItem, error) {
+	results := make([]serializers.SearchResponseItem, 0, len(comments))
+	if len(comments) == 0 {
+		return results, nil
+	}
+	issueIds := make([]primitive.ObjectID, len(comments))
+	for i, comment := range comments {
+		issueIds[i] = comment.Document.IssueId
+	}
+	issueOption := queries.NewOptions()
+	issueOption.SetOnlyFields("_id", "key", "title")
+	issues, err := queries.NewIssue(ctx.Context()).GetByIds(issueIds, issueOption)
+	if err != nil {
+		return nil, err
+	}
+	issueMap := make(map[primitive.ObjectID]models.Issue, len(issues))
+	for _, issue := range issues {
+		issueMap[issue.Id] = issue
+	}
+	for _, comment := range comments {
+		issue, ok := issueMap[comment.Document.IssueId]
+		if !ok {
+			continue
+		}
+		results = append(results, serializers.SearchResponseItem{
+			Type:        constants.SearchTypeComment,
+			Score:       comment.Score,
+			Title:       issue.Title,
+			Snippet:     textsearch.New().Snippet(comment.Document.Body, terms),
+			Key:         issue.Key,
+			WorkspaceId: comment.Document.WorkspaceId,
+			ProjectId:   &comment.Document.ProjectId,
+			IssueId:     &comment.Document.IssueId,
+			Id:          comment.Document.Id,
+		})
+	}
+	return results, nil
+}
+
+// snippet highlights the description, falling back to the title when the description has no match.
+func (s *service) snippet(title, description string, terms []string) string {
+	if !textsearch.New().Matches(description, terms) {
+		return textsearch.New().Snippet(title, terms)
+	}
+	return textsearch.New().Snippet(description, terms)
+}
@@ -0,0 +1,374 @@
+package sprint
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/request"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+)
+
+// sprintListFields is what the filter and sort query parameters of Search may refer to.
+var sprintListFields = queries.ListFields{
+	"name":         {Type: queries.FieldTypeString, Filterable: true, Sortable: true, Search: true},
+	"state":        {Type: queries.FieldTypeString, Filterable: true, Sortable: true},
+	"project_id":   {Type: queries.FieldTypeObjectId, Filterable: true},
+	"start_date":   {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+	"end_date":     {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+	"completed_at": {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+	"created_at":   {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+}
+
+type Controller interface {
+	Create(ctx *fiber.Ctx) error
+	Search(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	AddIssues(ctx *fiber.Ctx) error
+	RemoveIssues(ctx *fiber.Ctx) error
+	Start(ctx *fiber.Ctx) error
+	Complete(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Create(ctx *fiber.Ctx) error {
+	var requestBody serializers.SprintCreateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	if err := ctrl.service.ensureDates(requestBody.StartDate, requestBody.EndDate); err != nil {
+		return err
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	if !requestBody.ProjectId.IsZero() {
+		projectOption := queries.NewOptions()
+		projectOption.SetOnlyFields("_id")
+		if _, err := queries.NewProject(ctx.Context()).GetByIdAndWorkspaceId(requestBody.ProjectId, workspaceId, projectOption); err != nil {
+			return err
+		}
+	}
+	sprint, err := queries.NewSprint(ctx.Context()).Create(models.Sprint{
+		StartDate:   requestBody.StartDate,
+		EndDate:     requestBody.EndDate,
+		Name:        requestBody.Name,
+		Goal:        requestBody.Goal,
+		State:       constants.SprintStatePlanned,
+		WorkspaceId: workspaceId,
+		ProjectId:   requestBody.ProjectId,
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: fiber.Map{
+			"id": sprint.Id,
+		},
+	})
+}
+
+func (ctrl *controller) Search(ctx *fiber.Ctx) error {
+	var (
+		requestQuery serializers.SprintSearchQueryValidate
+		totalChan    = make(chan int64, 1)
+		errChan      = make(chan error, 1)
+	)
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	filter := bson.M{}
+	if requestQuery.State != "" {
+		filter["state"] = requestQuery.State
+	}
+	if requestQuery.ProjectId != "" {
+		filter["project_id"], _ = primitive.ObjectIDFromHex(requestQuery.ProjectId)
+	}
+	if err := sprintListFields.BuildFilter(filter, requestQuery.Filter); err != nil {
+		return err
+	}
+	sort, err := sprintListFields.ParseSort(requestQuery.Sort)
+	if err != nil {
+		return err
+	}
+	totalFilter := bson.M{}
+	for key, value := range filter {
+		totalFilter[key] = value
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	go func() {
+		total, err := queries.NewSprint(ctx.Context()).TotalByWorkspaceId(workspaceId, totalFilter)
+		errChan <- err
+		totalChan <- total
+	}()
+	pagination := request.NewPagination(requestQuery.Limit, requestQuery.Page)
+	queryOption := queries.NewOptions()
+	queryOption.SetPagination(pagination)
+	queryOption.AddSortKeys(sort)
+	queryOption.AddSortKey(map[string]int{"_id": -1})
+	sprints, err := queries.NewSprint(ctx.Context()).GetByWorkspaceId(workspaceId, filter, queryOption)
+	if err != nil {
+		return err
+	}
+	if err = <-errChan; err != nil {
+		return err
+	}
+	pagination.SetTotal(<-totalChan)
+	results := make([]serializers.SprintResponse, len(sprints))
+	for i := 0; i < len(sprints); i++ {
+		results[i] = ctrl.service.toResponse(sprints[i])
+	}
+	return response.NewArrayWithPagination(ctx, results, pagination)
+}
+
+func (ctrl *controller) Get(ctx *fiber.Ctx) error {
+	sprint, err := ctrl.getSprint(ctx)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*sprint),
+	})
+}
+
+func (ctrl *controller) Update(ctx *fiber.Ctx) error {
+	var requestBody serializers.SprintUpdateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	current, err := ctrl.getSprint(ctx)
+	if err != nil {
+		return err
+	}
+	if current.State == constants.SprintStateClosed {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{
+			Data:       respErr.ErrSprintStateInvalid,
+			ReturnCode: constants.ReturnCodeSprintStateInvalid,
+		})
+	}
+	data := bson.M{}
+	startDate, endDate := current.StartDate, current.EndDate
+	if requestBody.Name != nil {
+		data["name"] = *requestBody.Name
+	}
+	if requestBody.Goal != nil {
+		data["goal"] = *requestBody.Goal
+	}
+	if requestBody.StartDate != nil {
+		data["start_date"] = *requestBody.StartDate
+		startDate = requestBody.StartDate
+	}
+	if requestBody.EndDate != nil {
+		data["end_date"] = *requestBody.EndDate
+		endDate = requestBody.EndDate
+	}
+	if len(data) == 0 {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrValueIsNotAccepted,
+		})
+	}
+	if err = ctrl.service.ensureDates(startDate, endDate); err != nil {
+		return err
+	}
+	sprint, err := queries.NewSprint(ctx.Context()).UpdateByIdAndWorkspaceIdAndState(current.Id, current.WorkspaceId, current.State, data)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*sprint),
+	})
+}
+
+func (ctrl *controller) Delete(ctx *fiber.Ctx) error {
+	sprint, err := ctrl.getSprint(ctx, "_id", "workspace_id")
+	if err != nil {
+		return err
+	}
+	if err = ctrl.service.delete(ctx.Context(), sprint); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) AddIssues(ctx *fiber.Ctx) error {
+	var requestBody serializers.SprintIssuesBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	sprint, err := ctrl.getOpenSprint(ctx)
+	if err != nil {
+		return err
+	}
+	if err = ctrl.service.addIssues(ctx.Context(), sprint, uniqueObjectIds(requestBody.IssueIds)); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) RemoveIssues(ctx *fiber.Ctx) error {
+	var requestBody serializers.SprintIssuesBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	sprint, err := ctrl.getOpenSprint(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = queries.NewIssue(ctx.Context()).UpdateSprintByIdsAndWorkspaceId(requestBody.IssueIds, sprint.WorkspaceId, bson.M{"sprint_id": sprint.Id}, primitive.NilObjectID); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) Start(ctx *fiber.Ctx) error {
+	var requestBody serializers.SprintStartBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	current, err := ctrl.getSprint(ctx)
+	if err != nil {
+		return err
+	}
+	startDate, endDate := requestBody.StartDate, requestBody.EndDate
+	if startDate == nil {
+		now := time.Now()
+		startDate = &now
+	}
+	if endDate == nil {
+		endDate = current.EndDate
+	}
+	if endDate == nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "End date is required to start a sprint"})
+	}
+	if err = ctrl.service.ensureDates(startDate, endDate); err != nil {
+		return err
+	}
+	sprint, err := queries.NewSprint(ctx.Context()).UpdateByIdAndWorkspaceIdAndState(current.Id, current.WorkspaceId, constants.SprintStatePlanned, bson.M{
+		"state":      constants.SprintStateActive,
+		"start_date": *startDate,
+		"end_date":   *endDate,
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*sprint),
+	})
+}
+
+func (ctrl *controller) Complete(ctx *fiber.Ctx) error {
+	var requestBody serializers.SprintCompleteBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	current, err := ctrl.getSprint(ctx, "_id", "workspace_id", "project_id")
+	if err != nil {
+		return err
+	}
+	sprint, moved, err := ctrl.service.complete(ctx.Context(), current, requestBody.NextSprintId)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.SprintCompleteResponse{
+			Sprint:       ctrl.service.toResponse(*sprint),
+			MovedIssues:  moved,
+			NextSprintId: requestBody.NextSprintId,
+		},
+	})
+}
+
+func (ctrl *controller) getSprint(ctx *fiber.Ctx, fields ...string) (*models.Sprint, error) {
+	sprintId, err := primitive.ObjectIDFromHex(ctx.Params("sprintId"))
+	if err != nil {
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	queryOption := queries.NewOptions()
+	if len(fields) > 0 {
+		queryOption.SetOnlyFields(fields...)
+	}
+	return queries.NewSprint(ctx.Context()).GetByIdAndWorkspaceId(sprintId, local.New(ctx).GetWorkspace().Id, queryOption)
+}
+
+// getOpenSprint returns the sprint of the route when issues can still be added to or removed from it.
+func (ctrl *controller) getOpenSprint(ctx *fiber.Ctx) (*models.Sprint, error) {
+	sprint, err := ctrl.getSprint(ctx, "_id", "workspace_id", "project_id", "state")
+	if err != nil {
+		return nil, err
+	}
+	if sprint.State == constants.SprintStateClosed {
+		return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{
+			Data:       respErr.ErrSprintStateInvalid,
+			ReturnCode: constants.ReturnCodeSprintStateInvalid,
+		})
+	}
+	return sprint, nil
+}
+
+func uniqueObjectIds(ids []primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]struct{}, len(ids))
+	results := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			results = append(results, id)
+		}
+	}
+	return results
+}
@@ -0,0 +1,157 @@
+package sprint
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+)
+
+type serviceInterface interface {
+	ensureDates(startDate, endDate *time.Time) error
+	scopeFilter(sprint *models.Sprint) bson.M
+	complete(ctx context.Context, sprint *models.Sprint, nextSprintId primitive.ObjectID) (closed *models.Sprint, moved int64, err error)
+	delete(ctx context.Context, sprint *models.Sprint) error
+	addIssues(ctx context.Context, sprint *models.Sprint, issueIds []primitive.ObjectID) error
+	toResponse(sprint models.Sprint) serializers.SprintResponse
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+func (s *service) ensureDates(startDate, endDate *time.Time) error {
+	if startDate != nil && endDate != nil && !endDate.After(*startDate) {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "End date must be after start date"})
+	}
+	return nil
+}
+
+// scopeFilter limits issues to the project of a project sprint; workspace sprints accept any issue.
+func (s *service) scopeFilter(sprint *models.Sprint) bson.M {
+	if sprint.ProjectId.IsZero() {
+		return bson.M{}
+	}
+	return bson.M{"project_id": sprint.ProjectId}
+}
+
+// complete closes the sprint and moves its unfinished issues to the next sprint, or to the backlog when
+// nextSprintId is zero. Both happen in one transaction so a failure leaves the sprint active and untouched.
+func (s *service) complete(ctx context.Context, sprint *models.Sprint, nextSprintId primitive.ObjectID) (*models.Sprint, int64, error) {
+	workflowOption := queries.NewOptions()
+	workflowOption.SetOnlyFields("statuses")
+	workflow, err := queries.NewWorkflow(ctx).GetByWorkspaceId(sprint.WorkspaceId, workflowOption)
+	if err != nil {
+		return nil, 0, err
+	}
+	doneStatuses := workflow.StatusesInCategory(constants.WorkflowCategoryDone)
+	var (
+		closed *models.Sprint
+		moved  int64
+	)
+	err = queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if !nextSprintId.IsZero() {
+			next, err := queries.NewSprint(ctx).GetByIdAndWorkspaceId(nextSprintId, sprint.WorkspaceId)
+			if err != nil {
+				return err
+			}
+			if next.Id == sprint.Id || next.State == constants.SprintStateClosed || next.ProjectId != sprint.ProjectId {
+				return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Next sprint must be another open sprint of the same scope"})
+			}
+		}
+		var err error
+		if closed, err = queries.NewSprint(ctx).UpdateByIdAndWorkspaceIdAndState(sprint.Id, sprint.WorkspaceId, constants.SprintStateActive, bson.M{
+			"state":        constants.SprintStateClosed,
+			"completed_at": time.Now(),
+		}); err != nil {
+			return err
+		}
+		moved, err = queries.NewIssue(ctx).UpdateSprintBySprintId(sprint.Id, bson.M{"status": bson.M{"$nin": doneStatuses}}, nextSprintId)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return closed, moved, nil
+}
+
+// delete removes a planned sprint and returns its issues to the backlog in one transaction.
+func (s *service) delete(ctx context.Context, sprint *models.Sprint) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := queries.NewSprint(ctx).DeleteByIdAndWorkspaceIdAndState(sprint.Id, sprint.WorkspaceId, constants.SprintStatePlanned); err != nil {
+			return err
+		}
+		_, err := queries.NewIssue(ctx).UpdateSprintBySprintId(sprint.Id, bson.M{}, primitive.NilObjectID)
+		return err
+	})
+}
+
+// addIssues moves the issues into the sprint in one transaction with the checks it relies on: the
+// sprint is still open, every issue is in its scope and none is taken out of another active sprint.
+func (s *service) addIssues(ctx context.Context, sprint *models.Sprint, issueIds []primitive.ObjectID) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := queries.NewSprint(ctx).TouchOpenByIdAndWorkspaceId(sprint.Id, sprint.WorkspaceId); err != nil {
+			return err
+		}
+		filter := s.scopeFilter(sprint)
+		filter["_id"] = bson.M{"$in": issueIds}
+		total, err := queries.NewIssue(ctx).TotalByWorkspaceId(sprint.WorkspaceId, filter)
+		if err != nil {
+			return err
+		}
+		if total != int64(len(issueIds)) {
+			return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Some issues do not exist in the scope of this sprint"})
+		}
+		sprintOption := queries.NewOptions()
+		sprintOption.SetOnlyFields("_id")
+		activeSprints, err := queries.NewSprint(ctx).GetByWorkspaceId(sprint.WorkspaceId, bson.M{
+			"_id":   bson.M{"$ne": sprint.Id},
+			"state": constants.SprintStateActive,
+		}, sprintOption)
+		if err != nil {
+			return err
+		}
+		if len(activeSprints) > 0 {
+			activeSprintIds := make([]primitive.ObjectID, len(activeSprints))
+			for i := range activeSprints {
+				activeSprintIds[i] = activeSprints[i].Id
+			}
+			total, err = queries.NewIssue(ctx).TotalByWorkspaceId(sprint.WorkspaceId, bson.M{
+				"_id":       bson.M{"$in": issueIds},
+				"sprint_id": bson.M{"$in": activeSprintIds},
+			})
+			if err != nil {
+				return err
+			}
+			if total > 0 {
+				return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Some issues are in another active sprint, remove them from it first"})
+			}
+		}
+		_, err = queries.NewIssue(ctx).UpdateSprintByIdsAndWorkspaceId(issueIds, sprint.WorkspaceId, s.scopeFilter(sprint), sprint.Id)
+		return err
+	})
+}
+
+func (s *service) toResponse(sprint models.Sprint) serializers.SprintResponse {
+	return serializers.SprintResponse{
+		CreatedAt:   sprint.CreatedAt,
+		UpdatedAt:   sprint.UpdatedAt,
+		StartDate:   sprint.StartDate,
+		EndDate:     sprint.EndDate,
+		CompletedAt: sprint.CompletedAt,
+		Name:        sprint.Name,
+		Goal:        sprint.Goal,
+		State:       sprint.State,
+		ProjectId:   sprint.ProjectId,
+		Id:          sprint.Id,
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"jira-clone-api/common/logging"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/utilities/local"
+	objectStorage "jira-clone-api/utilities/storage"
+)
+
+var logger = logging.GetLogger()
+
+// Controller serves the signed URLs handed out by the local and memory storage drivers, standing in
+// for S3 pre-signed URLs. The signature is the only authorization these routes check.
+type Controller interface {
+	Upload(ctx *fiber.Ctx) error
+	Download(ctx *fiber.Ctx) error
+}
+
+type controller struct{}
+
+func New() Controller {
+	return &controller{}
+}
+
+func (ctrl *controller) Upload(ctx *fiber.Ctx) error {
+	name, query, err := ctrl.verify(ctx, http.MethodPut)
+	if err != nil {
+		return err
+	}
+	body := ctx.Body()
+	if strconv.Itoa(len(body)) != query.Get(objectStorage.ParamSize) {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: "Content-Length does not match the signed size",
+		})
+	}
+	if err = objectStorage.GetGlobal().PutObject(name, bytes.NewReader(body), int64(len(body)), ctx.Get(fiber.HeaderContentType)); err != nil {
+		logger.Error().Err(err).Str("function", "Upload").Str("functionInline", "objectStorage.GetGlobal().PutObject").Msg("storageController")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) Download(ctx *fiber.Ctx) error {
+	name, query, err := ctrl.verify(ctx, http.MethodGet)
+	if err != nil {
+		return err
+	}
+	object, info, err := objectStorage.GetGlobal().GetObject(name)
+	if err != nil {
+		if errors.Is(err, objectStorage.ErrObjectNotFound) {
+			return response.New(ctx, response.Options{
+				Code: fiber.StatusNotFound, Data: respErr.ErrResourceNotFound,
+			})
+		}
+		logger.Error().Err(err).Str("function", "Download").Str("functionInline", "objectStorage.GetGlobal().GetObject").Msg("storageController")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if disposition := query.Get(objectStorage.ParamDisposition); disposition != "" {
+		ctx.Set(fiber.HeaderContentDisposition, disposition)
+	}
+	if contentType := query.Get(objectStorage.ParamContentType); contentType != "" {
+		info.ContentType = contentType
+	}
+	ctx.Set(fiber.HeaderContentType, info.ContentType)
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	local.New(ctx).SetStatusCode(fiber.StatusOK)
+	return ctx.Status(fiber.StatusOK).SendStream(object, int(info.Size))
+}
+
+// verify returns the object key and query of a request whose signature matches method.
+func (ctrl *controller) verify(ctx *fiber.Ctx, method string) (string, url.Values, error) {
+	name, err := url.PathUnescape(ctx.Params("*"))
+	if err != nil {
+		return "", nil, response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	query, err := url.ParseQuery(string(ctx.Request().URI().QueryString()))
+	if err != nil {
+		return "", nil, response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	if err = objectStorage.VerifySignedUrl(method, name, query); err != nil {
+		return "", nil, response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	return name, query, nil
+}
@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+)
+
+type Controller interface {
+	Get(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Get(ctx *fiber.Ctx) error {
+	workflow, err := queries.NewWorkflow(ctx.Context()).GetByWorkspaceId(local.New(ctx).GetWorkspace().Id)
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*workflow),
+	})
+}
+
+func (ctrl *controller) Update(ctx *fiber.Ctx) error {
+	var requestBody serializers.WorkflowUpdateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	workflow, err := ctrl.service.buildWorkflow(requestBody)
+	if err != nil {
+		return err
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	currentOption := queries.NewOptions()
+	currentOption.SetOnlyFields("statuses")
+	current, err := queries.NewWorkflow(ctx.Context()).GetByWorkspaceId(workspaceId, currentOption)
+	if err != nil {
+		return err
+	}
+	removed := make([]string, 0)
+	for _, status := range current.Statuses {
+		if !workflow.HasStatus(status.Name) {
+			removed = append(removed, status.Name)
+		}
+	}
+	if len(removed) > 0 {
+		total, err := queries.NewIssue(ctx.Context()).TotalByWorkspaceId(workspaceId, bson.M{"status": bson.M{"$in": removed}})
+		if err != nil {
+			return err
+		}
+		if total > 0 {
+			return response.NewError(fiber.StatusConflict, response.ErrorOptions{
+				Data:       respErr.ErrWorkflowStatusInUse,
+				ReturnCode: constants.ReturnCodeWorkflowStatusInUse,
+			})
+		}
+	}
+	updated, err := queries.NewWorkflow(ctx.Context()).UpdateByWorkspaceId(workspaceId, bson.M{
+		"initial_status": workflow.InitialStatus,
+		"statuses":       workflow.Statuses,
+		"transitions":    workflow.Transitions,
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: ctrl.service.toResponse(*updated),
+	})
+}
@@ -0,0 +1,74 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+)
+
+type serviceInterface interface {
+	buildWorkflow(requestBody serializers.WorkflowUpdateBodyValidate) (*models.Workflow, error)
+	toResponse(workflow models.Workflow) serializers.WorkflowResponse
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// buildWorkflow checks that status names are unique and that the initial status and
+// every transition refer to declared statuses.
+func (s *service) buildWorkflow(requestBody serializers.WorkflowUpdateBodyValidate) (*models.Workflow, error) {
+	workflow := &models.Workflow{
+		InitialStatus: requestBody.InitialStatus,
+		Statuses:      make([]models.WorkflowStatus, len(requestBody.Statuses)),
+		Transitions:   make([]models.WorkflowTransition, 0, len(requestBody.Transitions)),
+	}
+	for i, status := range requestBody.Statuses {
+		if workflow.HasStatus(status.Name) {
+			return nil, invalidWorkflow(fmt.Sprintf("Status %q is declared more than once", status.Name))
+		}
+		workflow.Statuses[i] = models.WorkflowStatus{Name: status.Name, Category: status.Category}
+	}
+	if !workflow.HasStatus(workflow.InitialStatus) {
+		return nil, invalidWorkflow(fmt.Sprintf("Initial status %q is not declared", workflow.InitialStatus))
+	}
+	for _, transition := range requestBody.Transitions {
+		if !workflow.HasStatus(transition.From) || !workflow.HasStatus(transition.To) {
+			return nil, invalidWorkflow(fmt.Sprintf("Transition from %q to %q uses an undeclared status", transition.From, transition.To))
+		}
+		if transition.From == transition.To || workflow.HasTransition(transition.From, transition.To) {
+			continue
+		}
+		workflow.Transitions = append(workflow.Transitions, models.WorkflowTransition{From: transition.From, To: transition.To})
+	}
+	return workflow, nil
+}
+
+func invalidWorkflow(message string) error {
+	return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+		Data:       message,
+		ReturnCode: constants.ReturnCodeWorkflowInvalid,
+	})
+}
+
+func (s *service) toResponse(workflow models.Workflow) serializers.WorkflowResponse {
+	result := serializers.WorkflowResponse{
+		UpdatedAt:     workflow.UpdatedAt,
+		InitialStatus: workflow.InitialStatus,
+		Statuses:      make([]serializers.WorkflowStatusItem, len(workflow.Statuses)),
+		Transitions:   make([]serializers.WorkflowTransitionItem, len(workflow.Transitions)),
+	}
+	for i, status := range workflow.Statuses {
+		result.Statuses[i] = serializers.WorkflowStatusItem{Name: status.Name, Category: status.Category}
+	}
+	for i, transition := range workflow.Transitions {
+		result.Transitions[i] = serializers.WorkflowTransitionItem{From: transition.From, To: transition.To}
+	}
+	return result
+}
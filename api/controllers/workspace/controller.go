@@ -1,12 +1,15 @@
 package workspace
 
 import (
-	"path"
-	"path/filepath"
+	"errors"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"jira-clone-api/api/serializers"
 	"jira-clone-api/common/configure"
+	"jira-clone-api/common/constants"
 	"jira-clone-api/common/logging"
 	"jira-clone-api/common/request"
 	"jira-clone-api/common/response"
@@ -14,7 +17,6 @@ import (
 	"jira-clone-api/database/mongo/models"
 	"jira-clone-api/database/mongo/queries"
 	"jira-clone-api/utilities/local"
-	"jira-clone-api/utilities/storage_s3"
 )
 
 var (
@@ -22,9 +24,20 @@ var (
 	logger = logging.GetLogger()
 )
 
+// workspaceListFields is what the filter and sort parameters of Search may refer to.
+var workspaceListFields = queries.ListFields{
+	"name":       {Type: queries.FieldTypeString, Filterable: true, Sortable: true, Search: true},
+	"created_at": {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+	"updated_at": {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+}
+
 type Controller interface {
 	Create(ctx *fiber.Ctx) error
 	Search(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	Restore(ctx *fiber.Ctx) error
 }
 
 type controller struct {
@@ -44,37 +57,30 @@ func (ctrl *controller) Create(ctx *fiber.Ctx) error {
 			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
 		})
 	}
+	// The id is chosen up front so the image is stored under the workspace prefix.
+	workspaceId := primitive.NewObjectID()
 	image, _ := ctx.FormFile("image")
-	imageName := ""
+	uploaded := &models.Workspace{}
 	if image != nil {
-		if image.Size > 1024*1024 {
-			return response.New(ctx, response.Options{
-				Code: fiber.StatusBadRequest, Data: "Image size must be less than 1MB",
-			})
-		}
-		ext := filepath.Ext(image.Filename)
-		if ext != ".png" && ext != ".jpg" && ext != ".jpeg" && ext != ".svg" {
-			return response.New(ctx, response.Options{
-				Code: fiber.StatusBadRequest, Data: "Image must be png, jpg or jpeg",
-			})
-		}
-		if err := storage_s3.GetGlobal().UploadObject(image); err != nil {
+		var err error
+		if uploaded, err = ctrl.service.uploadImage(workspaceId, image); err != nil {
 			return err
 		}
-		imageName = image.Filename
 	}
-	workspace, err := queries.NewWorkspace(ctx.Context()).Create(models.Workspace{
-		Name:      requestBody.Name,
-		ImageName: imageName,
-		UserId:    local.New(ctx).GetUser().Id,
-	})
-	if err != nil {
+	if err := ctrl.service.create(ctx.Context(), models.Workspace{
+		Name:       requestBody.Name,
+		ImageName:  uploaded.ImageName,
+		ImageSizes: uploaded.ImageSizes,
+		UserId:     local.New(ctx).GetUser().Id,
+		Id:         workspaceId,
+	}); err != nil {
+		ctrl.service.deleteImage(uploaded)
 		return err
 	}
 	return response.New(ctx, response.Options{
 		Code: fiber.StatusOK,
 		Data: fiber.Map{
-			"id": workspace.Id,
+			"id": workspaceId,
 		},
 	})
 }
@@ -93,18 +99,45 @@ func (ctrl *controller) Search(ctx *fiber.Ctx) error {
 	if err := requestBody.Validate(); err != nil {
 		return err
 	}
-	userId := local.New(ctx).GetUser().Id
+	filter := bson.M{}
+	if requestBody.Name != "" {
+		filter = queries.NewSearchFilter("name", queries.QueryMethodContains, requestBody.Name)
+	}
+	if err := workspaceListFields.BuildFilter(filter, requestBody.Filter); err != nil {
+		return err
+	}
+	sort, err := workspaceListFields.ParseSort(requestBody.Sort)
+	if err != nil {
+		return err
+	}
+	totalFilter := bson.M{}
+	for key, value := range filter {
+		totalFilter[key] = value
+	}
+	memberOption := queries.NewOptions()
+	memberOption.SetOnlyFields("workspace_id", "role")
+	members, err := queries.NewWorkspaceMember(ctx.Context()).GetByUserId(local.New(ctx).GetUser().Id, memberOption)
+	if err != nil {
+		return err
+	}
+	roles := make(map[primitive.ObjectID]string, len(members))
+	workspaceIds := make([]primitive.ObjectID, len(members))
+	for i := 0; i < len(members); i++ {
+		roles[members[i].WorkspaceId] = members[i].Role
+		workspaceIds[i] = members[i].WorkspaceId
+	}
 	go func() {
-		total, err := queries.NewWorkspace(ctx.Context()).TotalByNameRegexAndUserId(requestBody.Name, userId)
+		total, err := queries.NewWorkspace(ctx.Context()).TotalByIdsAndFilter(workspaceIds, totalFilter)
 		errChan <- err
 		totalChan <- total
 	}()
 	pagination := request.NewPagination(requestBody.Limit, requestBody.Page)
 	queryOption := queries.NewOptions()
 	queryOption.SetPagination(pagination)
+	queryOption.AddSortKeys(sort)
 	queryOption.AddSortKey(map[string]int{"_id": -1})
-	queryOption.SetOnlyFields("_id", "name", "created_at", "updated_at", "image_name")
-	workspaces, err := queries.NewWorkspace(ctx.Context()).GetByNameRegexAndUserId(requestBody.Name, userId, queryOption)
+	queryOption.SetOnlyFields("_id", "name", "created_at", "updated_at", "image_name", "image_sizes")
+	workspaces, err := queries.NewWorkspace(ctx.Context()).GetByIdsAndFilter(workspaceIds, filter, queryOption)
 	if err != nil {
 		return err
 	}
@@ -117,8 +150,118 @@ func (ctrl *controller) Search(ctx *fiber.Ctx) error {
 		results[i].Name = workspaces[i].Name
 		results[i].CreatedAt = workspaces[i].CreatedAt
 		results[i].UpdatedAt = workspaces[i].UpdatedAt
-		results[i].ImageUrl = path.Join(cfg.S3Prefix, workspaces[i].ImageName)
+		results[i].ImageUrls = ctrl.service.imageUrls(&workspaces[i])
+		results[i].Role = roles[workspaces[i].Id]
 		results[i].Id = workspaces[i].Id
 	}
 	return response.NewArrayWithPagination(ctx, results, pagination)
 }
+
+func (ctrl *controller) Get(ctx *fiber.Ctx) error {
+	localService := local.New(ctx)
+	workspace := localService.GetWorkspace()
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.WorkspaceGetResponse{
+			CreatedAt: workspace.CreatedAt,
+			UpdatedAt: workspace.UpdatedAt,
+			ImageUrls: ctrl.service.imageUrls(&workspace),
+			Name:      workspace.Name,
+			Role:      localService.GetWorkspaceRole(),
+			Id:        workspace.Id,
+		},
+	})
+}
+
+func (ctrl *controller) Update(ctx *fiber.Ctx) error {
+	var requestBody serializers.WorkspaceUpdateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	current := localService.GetWorkspace()
+	data := bson.M{}
+	if requestBody.Name != "" {
+		data["name"] = requestBody.Name
+	}
+	image, _ := ctx.FormFile("image")
+	uploaded := &models.Workspace{}
+	if image != nil {
+		var err error
+		if uploaded, err = ctrl.service.uploadImage(current.Id, image); err != nil {
+			return err
+		}
+		data["image_name"] = uploaded.ImageName
+		data["image_sizes"] = uploaded.ImageSizes
+	}
+	if len(data) == 0 {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrValueIsNotAccepted,
+		})
+	}
+	workspace, err := queries.NewWorkspace(ctx.Context()).UpdateById(current.Id, data)
+	if err != nil {
+		ctrl.service.deleteImage(uploaded)
+		return err
+	}
+	if image != nil && current.ImageName != workspace.ImageName {
+		ctrl.service.deleteImage(&current)
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.WorkspaceGetResponse{
+			CreatedAt: workspace.CreatedAt,
+			UpdatedAt: workspace.UpdatedAt,
+			ImageUrls: ctrl.service.imageUrls(workspace),
+			Name:      workspace.Name,
+			Role:      localService.GetWorkspaceRole(),
+			Id:        workspace.Id,
+		},
+	})
+}
+
+func (ctrl *controller) Delete(ctx *fiber.Ctx) error {
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	deletedAt, err := queries.NewWorkspace(ctx.Context()).SoftDeleteById(workspaceId)
+	if err != nil {
+		return err
+	}
+	if err = queries.NewWorkspaceInvitation(ctx.Context()).RevokeByWorkspaceId(workspaceId); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.WorkspaceDeleteResponse{
+			RestorableUntil: deletedAt.Add(cfg.WorkspaceRestoreWindow),
+		},
+	})
+}
+
+func (ctrl *controller) Restore(ctx *fiber.Ctx) error {
+	workspaceId, err := primitive.ObjectIDFromHex(ctx.Params("workspaceId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	// The workspace middleware skips deleted workspaces, so the owner check is done here.
+	memberOption := queries.NewOptions()
+	memberOption.SetOnlyFields("role")
+	member, err := queries.NewWorkspaceMember(ctx.Context()).GetByWorkspaceIdAndUserId(workspaceId, local.New(ctx).GetUser().Id, memberOption)
+	if err != nil {
+		if e := new(response.Error); errors.As(err, &e) && e.Code == fiber.StatusNotFound {
+			return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+		}
+		return err
+	}
+	if member.Role != constants.WorkspaceRoleOwner {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	if err = queries.NewWorkspace(ctx.Context()).RestoreById(workspaceId, time.Now().Add(-cfg.WorkspaceRestoreWindow)); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
@@ -1,9 +1,116 @@
 package workspace
 
-type serviceInterface interface{}
+import (
+	"bytes"
+	"context"
+	"image"
+	"mime/multipart"
+	"path"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/imaging"
+	"jira-clone-api/utilities/storage"
+)
+
+type serviceInterface interface {
+	create(ctx context.Context, workspace models.Workspace) error
+	uploadImage(workspaceId primitive.ObjectID, file *multipart.FileHeader) (uploaded *models.Workspace, err error)
+	deleteImage(workspace *models.Workspace)
+	imageUrls(workspace *models.Workspace) map[string]string
+}
 
 type service struct{}
 
 func newService() serviceInterface {
 	return &service{}
 }
+
+// create inserts the workspace together with its owner membership and default workflow in one
+// transaction, so a workspace never exists without an owner or a workflow.
+func (s *service) create(ctx context.Context, workspace models.Workspace) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := queries.NewWorkspace(ctx).Create(workspace); err != nil {
+			return err
+		}
+		if _, err := queries.NewWorkspaceMember(ctx).Create(models.WorkspaceMember{
+			Role:        constants.WorkspaceRoleOwner,
+			WorkspaceId: workspace.Id,
+			UserId:      workspace.UserId,
+		}); err != nil {
+			return err
+		}
+		_, err := queries.NewWorkflow(ctx).Create(models.NewDefaultWorkflow(workspace.Id))
+		return err
+	})
+}
+
+// uploadImage decodes the upload, re-encodes it without metadata and stores it together with its
+// thumbnails. The returned workspace only carries the image fields to save.
+func (s *service) uploadImage(workspaceId primitive.ObjectID, file *multipart.FileHeader) (*models.Workspace, error) {
+	if file.Size > 1024*1024 {
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Image size must be less than 1MB"})
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Image cannot be read"})
+	}
+	defer reader.Close()
+	imagingService := imaging.New()
+	img, format, err := imagingService.Decode(reader)
+	if err != nil {
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: "Image must be png, jpg or jpeg"})
+	}
+	ext := ".png"
+	if format == imaging.FormatJPEG {
+		ext = ".jpg"
+	}
+	uploaded := &models.Workspace{
+		ImageName:  path.Join("workspaces", workspaceId.Hex(), uuid.NewString()+ext),
+		ImageSizes: constants.WorkspaceImageSizes,
+	}
+	variants := []image.Image{img}
+	for _, size := range uploaded.ImageSizes {
+		variants = append(variants, imagingService.Thumbnail(img, size))
+	}
+	for i, key := range uploaded.ImageKeys() {
+		var buffer bytes.Buffer
+		if err = imagingService.Encode(&buffer, variants[i], format); err == nil {
+			err = storage.GetGlobal().PutObject(key, &buffer, int64(buffer.Len()), imagingService.ContentType(format))
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("function", "uploadImage").Str("functionInline", "storage.GetGlobal().PutObject").Msg("workspaceService")
+			s.deleteImage(uploaded)
+			return nil, response.NewError(fiber.StatusInternalServerError)
+		}
+	}
+	return uploaded, nil
+}
+
+// deleteImage removes the image and its thumbnails; failures are only logged.
+func (s *service) deleteImage(workspace *models.Workspace) {
+	for _, key := range workspace.ImageKeys() {
+		if err := storage.GetGlobal().DeleteObject(key); err != nil {
+			logger.Error().Err(err).Str("function", "deleteImage").Str("functionInline", "storage.GetGlobal().DeleteObject").Msg("workspaceService")
+		}
+	}
+}
+
+// imageUrls maps "original" and each stored thumbnail size to a public URL, or returns nil without an image.
+func (s *service) imageUrls(workspace *models.Workspace) map[string]string {
+	if workspace.ImageName == "" {
+		return nil
+	}
+	store := storage.GetGlobal()
+	urls := map[string]string{"original": store.PublicUrl(workspace.ImageName)}
+	for _, size := range workspace.ImageSizes {
+		urls[strconv.Itoa(size)] = store.PublicUrl(workspace.ImageKey(size))
+	}
+	return urls
+}
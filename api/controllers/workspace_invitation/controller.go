@@ -0,0 +1,183 @@
+package workspace_invitation
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/configure"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/logging"
+	"jira-clone-api/common/request"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/jwt"
+	"jira-clone-api/utilities/local"
+)
+
+var (
+	cfg    = configure.GetConfig()
+	logger = logging.GetLogger()
+)
+
+// invitationListFields is what the filter and sort query parameters of Search may refer to.
+var invitationListFields = queries.ListFields{
+	"email":      {Type: queries.FieldTypeString, Filterable: true, Sortable: true},
+	"expired_at": {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+}
+
+type Controller interface {
+	Create(ctx *fiber.Ctx) error
+	Search(ctx *fiber.Ctx) error
+	Revoke(ctx *fiber.Ctx) error
+	Accept(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Create(ctx *fiber.Ctx) error {
+	var requestBody serializers.WorkspaceInvitationCreateBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	if !constants.CanManageWorkspaceRole(localService.GetWorkspaceRole(), requestBody.Role) {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	invitation, err := queries.NewWorkspaceInvitation(ctx.Context()).Create(models.WorkspaceInvitation{
+		ExpiredAt:   time.Now().Add(cfg.InvitationTimeout),
+		Email:       requestBody.Email,
+		Role:        requestBody.Role,
+		WorkspaceId: localService.GetWorkspace().Id,
+		CreatedBy:   localService.GetUser().Id,
+	})
+	if err != nil {
+		return err
+	}
+	code, err := jwt.GetGlobal().GenerateTransactionToken(invitation.Id.Hex(), jwt.TransactionWorkspaceInvitation, cfg.InvitationTimeout)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "jwt.GetGlobal().GenerateTransactionToken").Msg("workspaceInvitationController")
+		return response.New(ctx, response.Options{Code: fiber.StatusInternalServerError})
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.WorkspaceInvitationCreateResponse{
+			ExpiredAt: invitation.ExpiredAt,
+			Code:      code,
+			Id:        invitation.Id,
+		},
+	})
+}
+
+func (ctrl *controller) Search(ctx *fiber.Ctx) error {
+	var (
+		requestQuery serializers.WorkspaceInvitationSearchQueryValidate
+		totalChan    = make(chan int64, 1)
+		errChan      = make(chan error, 1)
+	)
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	filter := bson.M{}
+	if err := invitationListFields.BuildFilter(filter, requestQuery.Filter); err != nil {
+		return err
+	}
+	sort, err := invitationListFields.ParseSort(requestQuery.Sort)
+	if err != nil {
+		return err
+	}
+	totalFilter := bson.M{}
+	for key, value := range filter {
+		totalFilter[key] = value
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	go func() {
+		total, err := queries.NewWorkspaceInvitation(ctx.Context()).TotalPendingByWorkspaceId(workspaceId, totalFilter)
+		errChan <- err
+		totalChan <- total
+	}()
+	pagination := request.NewPagination(requestQuery.Limit, requestQuery.Page)
+	queryOption := queries.NewOptions()
+	queryOption.SetPagination(pagination)
+	queryOption.AddSortKeys(sort)
+	queryOption.AddSortKey(map[string]int{"_id": queries.SortTypeDesc})
+	queryOption.SetOnlyFields("_id", "created_at", "expired_at", "email", "role", "created_by")
+	invitations, err := queries.NewWorkspaceInvitation(ctx.Context()).GetPendingByWorkspaceId(workspaceId, filter, queryOption)
+	if err != nil {
+		return err
+	}
+	if err = <-errChan; err != nil {
+		return err
+	}
+	pagination.SetTotal(<-totalChan)
+	results := make([]serializers.WorkspaceInvitationResponseItem, len(invitations))
+	for i := 0; i < len(invitations); i++ {
+		results[i].CreatedAt = invitations[i].CreatedAt
+		results[i].ExpiredAt = invitations[i].ExpiredAt
+		results[i].Email = invitations[i].Email
+		results[i].Role = invitations[i].Role
+		results[i].CreatedBy = invitations[i].CreatedBy
+		results[i].Id = invitations[i].Id
+	}
+	return response.NewArrayWithPagination(ctx, results, pagination)
+}
+
+func (ctrl *controller) Revoke(ctx *fiber.Ctx) error {
+	invitationId, err := primitive.ObjectIDFromHex(ctx.Params("invitationId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	if err = queries.NewWorkspaceInvitation(ctx.Context()).RevokeByIdAndWorkspaceId(invitationId, local.New(ctx).GetWorkspace().Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) Accept(ctx *fiber.Ctx) error {
+	var requestBody serializers.WorkspaceInvitationAcceptBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	user := local.New(ctx).GetUser()
+	invitation, err := ctrl.service.getValidInvitation(ctx.Context(), requestBody.Code, user)
+	if err != nil {
+		return err
+	}
+	if err = ctrl.service.accept(ctx.Context(), invitation, user.Id); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: serializers.WorkspaceInvitationAcceptResponse{
+			Role:        invitation.Role,
+			WorkspaceId: invitation.WorkspaceId,
+		},
+	})
+}
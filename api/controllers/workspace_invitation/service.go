@@ -0,0 +1,80 @@
+package workspace_invitation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/jwt"
+)
+
+type serviceInterface interface {
+	getValidInvitation(ctx context.Context, code string, user models.User) (*models.WorkspaceInvitation, error)
+	accept(ctx context.Context, invitation *models.WorkspaceInvitation, userId primitive.ObjectID) error
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// getValidInvitation resolves an invitation code and checks that the given user may still accept it.
+func (s *service) getValidInvitation(ctx context.Context, code string, user models.User) (*models.WorkspaceInvitation, error) {
+	transactionId, err := jwt.GetGlobal().ValidateTransactionToken(code, jwt.TransactionWorkspaceInvitation)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, response.NewError(fiber.StatusGone, response.ErrorOptions{Data: respErr.ErrTransactionExpired})
+		}
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrValueIsNotAccepted})
+	}
+	invitationId, err := primitive.ObjectIDFromHex(transactionId)
+	if err != nil {
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrValueIsNotAccepted})
+	}
+	invitation, err := queries.NewWorkspaceInvitation(ctx).GetById(invitationId)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case invitation.RevokedAt != nil:
+		return nil, response.NewError(fiber.StatusGone, response.ErrorOptions{Data: respErr.ErrTransactionRevoked})
+	case time.Now().After(invitation.ExpiredAt):
+		return nil, response.NewError(fiber.StatusGone, response.ErrorOptions{Data: respErr.ErrTransactionExpired})
+	case invitation.IsShareableLink():
+		return invitation, nil
+	case invitation.AcceptedAt != nil:
+		return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: respErr.ErrTransactionUsed})
+	case !strings.EqualFold(invitation.Email, user.Email):
+		return nil, response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	case !user.IsEmailVerified():
+		// Emails are neither unique nor verified at register, so only a verified one proves ownership.
+		return nil, response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrEmailNotVerified})
+	}
+	return invitation, nil
+}
+
+// accept consumes an email invitation and adds the member in one transaction, so a failed insert
+// leaves the invitation usable and a used invitation always has its member.
+func (s *service) accept(ctx context.Context, invitation *models.WorkspaceInvitation, userId primitive.ObjectID) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if !invitation.IsShareableLink() {
+			if err := queries.NewWorkspaceInvitation(ctx).MarkAcceptedById(invitation.Id, userId); err != nil {
+				return err
+			}
+		}
+		_, err := queries.NewWorkspaceMember(ctx).Create(models.WorkspaceMember{
+			Role:        invitation.Role,
+			WorkspaceId: invitation.WorkspaceId,
+			UserId:      userId,
+		})
+		return err
+	})
+}
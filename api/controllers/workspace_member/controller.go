@@ -0,0 +1,202 @@
+package workspace_member
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/api/serializers"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/request"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+)
+
+// memberListFields is what the filter and sort query parameters of Search may refer to.
+var memberListFields = queries.ListFields{
+	"role":       {Type: queries.FieldTypeString, Filterable: true, Sortable: true},
+	"created_at": {Type: queries.FieldTypeDate, Filterable: true, Sortable: true},
+}
+
+type Controller interface {
+	Add(ctx *fiber.Ctx) error
+	Search(ctx *fiber.Ctx) error
+	UpdateRole(ctx *fiber.Ctx) error
+	Remove(ctx *fiber.Ctx) error
+}
+
+type controller struct {
+	service serviceInterface
+}
+
+func New() Controller {
+	return &controller{
+		service: newService(),
+	}
+}
+
+func (ctrl *controller) Add(ctx *fiber.Ctx) error {
+	var requestBody serializers.WorkspaceMemberAddBodyValidate
+	if err := ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	if !constants.CanManageWorkspaceRole(localService.GetWorkspaceRole(), requestBody.Role) {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	queryOption := queries.NewOptions()
+	queryOption.SetOnlyFields("_id")
+	user, err := queries.NewUser(ctx.Context()).GetByUsername(requestBody.Username, queryOption)
+	if err != nil {
+		return err
+	}
+	member, err := queries.NewWorkspaceMember(ctx.Context()).Create(models.WorkspaceMember{
+		Role:        requestBody.Role,
+		WorkspaceId: localService.GetWorkspace().Id,
+		UserId:      user.Id,
+	})
+	if err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{
+		Code: fiber.StatusOK,
+		Data: fiber.Map{
+			"id": member.Id,
+		},
+	})
+}
+
+func (ctrl *controller) Search(ctx *fiber.Ctx) error {
+	var (
+		requestQuery serializers.WorkspaceMemberSearchQueryValidate
+		totalChan    = make(chan int64, 1)
+		errChan      = make(chan error, 1)
+	)
+	if err := ctx.QueryParser(&requestQuery); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err := requestQuery.Validate(); err != nil {
+		return err
+	}
+	filter := bson.M{}
+	if err := memberListFields.BuildFilter(filter, requestQuery.Filter); err != nil {
+		return err
+	}
+	sort, err := memberListFields.ParseSort(requestQuery.Sort)
+	if err != nil {
+		return err
+	}
+	totalFilter := bson.M{}
+	for key, value := range filter {
+		totalFilter[key] = value
+	}
+	workspaceId := local.New(ctx).GetWorkspace().Id
+	go func() {
+		total, err := queries.NewWorkspaceMember(ctx.Context()).TotalByWorkspaceId(workspaceId, totalFilter)
+		errChan <- err
+		totalChan <- total
+	}()
+	pagination := request.NewPagination(requestQuery.Limit, requestQuery.Page)
+	queryOption := queries.NewOptions()
+	queryOption.SetPagination(pagination)
+	queryOption.AddSortKeys(sort)
+	queryOption.AddSortKey(map[string]int{"_id": queries.SortTypeAsc})
+	queryOption.SetOnlyFields("user_id", "role", "created_at")
+	members, err := queries.NewWorkspaceMember(ctx.Context()).GetByWorkspaceId(workspaceId, filter, queryOption)
+	if err != nil {
+		return err
+	}
+	if err = <-errChan; err != nil {
+		return err
+	}
+	pagination.SetTotal(<-totalChan)
+	userIds := make([]primitive.ObjectID, len(members))
+	for i := 0; i < len(members); i++ {
+		userIds[i] = members[i].UserId
+	}
+	userOption := queries.NewOptions()
+	userOption.SetOnlyFields("_id", "username", "email")
+	users, err := queries.NewUser(ctx.Context()).GetByIds(userIds, userOption)
+	if err != nil {
+		return err
+	}
+	userMap := make(map[primitive.ObjectID]models.User, len(users))
+	for _, user := range users {
+		userMap[user.Id] = user
+	}
+	results := make([]serializers.WorkspaceMemberResponseItem, len(members))
+	for i := 0; i < len(members); i++ {
+		results[i].JoinedAt = members[i].CreatedAt
+		results[i].Username = userMap[members[i].UserId].Username
+		results[i].Email = userMap[members[i].UserId].Email
+		results[i].Role = members[i].Role
+		results[i].UserId = members[i].UserId
+	}
+	return response.NewArrayWithPagination(ctx, results, pagination)
+}
+
+func (ctrl *controller) UpdateRole(ctx *fiber.Ctx) error {
+	userId, err := primitive.ObjectIDFromHex(ctx.Params("userId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	var requestBody serializers.WorkspaceMemberUpdateBodyValidate
+	if err = ctx.BodyParser(&requestBody); err != nil {
+		return response.New(ctx, response.Options{
+			Code: fiber.StatusBadRequest, Data: respErr.ErrFieldWrongType,
+		})
+	}
+	if err = requestBody.Validate(); err != nil {
+		return err
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	callerRole := localService.GetWorkspaceRole()
+	memberQuery := queries.NewWorkspaceMember(ctx.Context())
+	target, err := memberQuery.GetByWorkspaceIdAndUserId(workspaceId, userId)
+	if err != nil {
+		return err
+	}
+	if !constants.CanManageWorkspaceRole(callerRole, target.Role) || !constants.CanManageWorkspaceRole(callerRole, requestBody.Role) {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	if target.Role == requestBody.Role {
+		return response.New(ctx, response.Options{Code: fiber.StatusOK})
+	}
+	if err = ctrl.service.updateRole(ctx.Context(), target, requestBody.Role); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
+
+func (ctrl *controller) Remove(ctx *fiber.Ctx) error {
+	userId, err := primitive.ObjectIDFromHex(ctx.Params("userId"))
+	if err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+	}
+	localService := local.New(ctx)
+	workspaceId := localService.GetWorkspace().Id
+	callerId := localService.GetUser().Id
+	memberQuery := queries.NewWorkspaceMember(ctx.Context())
+	target, err := memberQuery.GetByWorkspaceIdAndUserId(workspaceId, userId)
+	if err != nil {
+		return err
+	}
+	// Anyone may leave a workspace, removing somebody else needs a higher role.
+	if userId != callerId && !constants.CanManageWorkspaceRole(localService.GetWorkspaceRole(), target.Role) {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	if err = ctrl.service.remove(ctx.Context(), target); err != nil {
+		return err
+	}
+	return response.New(ctx, response.Options{Code: fiber.StatusOK})
+}
@@ -0,0 +1,78 @@
+package workspace_member
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+)
+
+type serviceInterface interface {
+	updateRole(ctx context.Context, target *models.WorkspaceMember, role string) error
+	remove(ctx context.Context, target *models.WorkspaceMember) error
+}
+
+type service struct{}
+
+func newService() serviceInterface {
+	return &service{}
+}
+
+// updateRole gives target another role in one transaction with the check that an owner remains.
+func (s *service) updateRole(ctx context.Context, target *models.WorkspaceMember, role string) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ensureOwnerRemains(ctx, target); err != nil {
+			return err
+		}
+		return queries.NewWorkspaceMember(ctx).UpdateRoleByWorkspaceIdAndUserId(target.WorkspaceId, target.UserId, role)
+	})
+}
+
+// remove deletes target and its saved filters in one transaction with the check that an owner remains.
+func (s *service) remove(ctx context.Context, target *models.WorkspaceMember) error {
+	return queries.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ensureOwnerRemains(ctx, target); err != nil {
+			return err
+		}
+		if err := queries.NewWorkspaceMember(ctx).DeleteByWorkspaceIdAndUserId(target.WorkspaceId, target.UserId); err != nil {
+			return err
+		}
+		return queries.NewSavedFilter(ctx).DeleteByUserIdAndWorkspaceId(target.UserId, target.WorkspaceId)
+	})
+}
+
+// ensureOwnerRemains rejects demoting or removing the target when it is the workspace's last owner,
+// and when its role changed since the caller's permissions were checked against it. It must run in
+// the transaction of the write: owners are counted after touching the workspace, so concurrent
+// demotions of two owners conflict there and the retried one sees the other's result.
+func (s *service) ensureOwnerRemains(ctx context.Context, target *models.WorkspaceMember) error {
+	if target.Role == constants.WorkspaceRoleOwner {
+		if err := queries.NewWorkspace(ctx).TouchById(target.WorkspaceId); err != nil {
+			return err
+		}
+	}
+	memberOption := queries.NewOptions()
+	memberOption.SetOnlyFields("role")
+	current, err := queries.NewWorkspaceMember(ctx).GetByWorkspaceIdAndUserId(target.WorkspaceId, target.UserId, memberOption)
+	if err != nil {
+		return err
+	}
+	if current.Role != target.Role {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Workspace member was changed by someone else, reload it and retry"})
+	}
+	if target.Role != constants.WorkspaceRoleOwner {
+		return nil
+	}
+	total, err := queries.NewWorkspaceMember(ctx).TotalByWorkspaceIdAndRole(target.WorkspaceId, constants.WorkspaceRoleOwner)
+	if err != nil {
+		return err
+	}
+	if total <= 1 {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: respErr.ErrLastOwnerRequired})
+	}
+	return nil
+}
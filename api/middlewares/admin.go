@@ -0,0 +1,17 @@
+package authenticate
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/utilities/local"
+)
+
+// RequireAdmin lets the request through only for users flagged as administrators.
+// It must be mounted after AccessToken.
+func RequireAdmin(ctx *fiber.Ctx) error {
+	if !local.New(ctx).GetUser().IsAdmin {
+		return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+	}
+	return ctx.Next()
+}
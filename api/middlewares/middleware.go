@@ -2,6 +2,7 @@ package authenticate
 
 import (
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,6 +16,9 @@ import (
 
 var cfg = configure.GetConfig()
 
+// tokenTouchInterval limits how often a session's last-used time is written back.
+const tokenTouchInterval = time.Minute
+
 func RefreshToken(ctx *fiber.Ctx) error {
 	tokenString := ctx.Get("Authorization")
 	if tokenString == "" {
@@ -36,12 +40,20 @@ func RefreshToken(ctx *fiber.Ctx) error {
 		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenWrong})
 	}
 	queryOption := queries.NewOptions()
-	queryOption.SetOnlyFields("_id", "user_id")
-	token, err := queries.NewToken(ctx.Context()).GetById(tokenId, queryOption)
+	queryOption.SetOnlyFields("_id", "user_id", "family_id", "revoked_at")
+	tokenQuery := queries.NewToken(ctx.Context())
+	token, err := tokenQuery.GetById(tokenId, queryOption)
 	if err != nil {
 		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenRevoked})
 	}
-	queryOption.SetOnlyFields("_id", "email", "username")
+	if token.IsRevoked() {
+		// A refresh token is single-use, seeing it again means it was stolen: drop the whole family.
+		if err = tokenQuery.DeleteByFamilyId(token.FamilyId); err != nil {
+			return err
+		}
+		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenRevoked})
+	}
+	queryOption.SetOnlyFields("_id", "email", "username", "email_verified_at", "mfa_enabled_at", "is_admin")
 	user, err := queries.NewUser(ctx.Context()).GetById(token.UserId, queryOption)
 	if err != nil {
 		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: "User not found"})
@@ -65,6 +77,9 @@ func AccessToken(ctx *fiber.Ctx) error {
 	if err != nil {
 		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenWrong})
 	}
+	if payload.IsMfaPendingToken() {
+		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrMfaRequired})
+	}
 	if !payload.IsAccessToken() {
 		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenWrong})
 	}
@@ -73,16 +88,24 @@ func AccessToken(ctx *fiber.Ctx) error {
 		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenWrong})
 	}
 	opt := queries.NewOptions()
-	opt.SetOnlyFields("_id", "user_id")
-	tok, err := queries.NewToken(ctx.Context()).GetById(tokenId, opt)
-	if err != nil {
+	opt.SetOnlyFields("_id", "user_id", "revoked_at", "last_used_at")
+	tokenQuery := queries.NewToken(ctx.Context())
+	tok, err := tokenQuery.GetById(tokenId, opt)
+	if err != nil || tok.IsRevoked() {
 		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenRevoked})
 	}
-	opt.SetOnlyFields("_id", "email", "username")
+	if time.Since(tok.LastUsedAt) > tokenTouchInterval {
+		if err = tokenQuery.TouchById(tokenId, ctx.IP()); err != nil {
+			return err
+		}
+	}
+	opt.SetOnlyFields("_id", "email", "username", "email_verified_at", "mfa_enabled_at", "is_admin")
 	user, err := queries.NewUser(ctx.Context()).GetById(tok.UserId, opt)
 	if err != nil {
 		return response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: "User not found"})
 	}
-	local.New(ctx).SetUser(*user)
+	localService := local.New(ctx)
+	localService.SetUser(*user)
+	localService.SetTokenId(tokenId)
 	return ctx.Next()
 }
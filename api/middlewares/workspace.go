@@ -0,0 +1,45 @@
+package authenticate
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/local"
+)
+
+// RequireWorkspaceRole resolves the :workspaceId path parameter and lets the request through only
+// when the current user is a member of that workspace with one of the given roles.
+// Without roles any member is accepted. It must be mounted after AccessToken.
+func RequireWorkspaceRole(roles ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		workspaceId, err := primitive.ObjectIDFromHex(ctx.Params("workspaceId"))
+		if err != nil {
+			return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+		}
+		localService := local.New(ctx)
+		memberOption := queries.NewOptions()
+		memberOption.SetOnlyFields("role")
+		member, err := queries.NewWorkspaceMember(ctx.Context()).GetByWorkspaceIdAndUserId(workspaceId, localService.GetUser().Id, memberOption)
+		if err != nil {
+			if e := new(response.Error); errors.As(err, &e) && e.Code == fiber.StatusNotFound {
+				return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+			}
+			return err
+		}
+		if len(roles) > 0 && !slices.Contains(roles, member.Role) {
+			return response.NewError(fiber.StatusForbidden, response.ErrorOptions{Data: respErr.ErrPermissionDenied})
+		}
+		workspace, err := queries.NewWorkspace(ctx.Context()).GetById(workspaceId)
+		if err != nil {
+			return err
+		}
+		localService.SetWorkspace(*workspace)
+		localService.SetWorkspaceRole(member.Role)
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	adminCtrl "jira-clone-api/api/controllers/admin"
+	authMiddleware "jira-clone-api/api/middlewares"
+)
+
+type Admin interface {
+	V1()
+}
+type admin struct {
+	router fiber.Router
+	ctrl   adminCtrl.Controller
+}
+
+func NewAdmin(router fiber.Router) Admin {
+	return &admin{router: router.Group("/admin", authMiddleware.AccessToken, authMiddleware.RequireAdmin), ctrl: adminCtrl.New()}
+}
+
+func (r admin) V1() {
+	r.users()
+}
+
+func (r admin) users() {
+	r.router.Post("/users/:userId/unlock", r.ctrl.UnlockUser)
+}
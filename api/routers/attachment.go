@@ -0,0 +1,33 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	attachmentCtrl "jira-clone-api/api/controllers/attachment"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type Attachment interface {
+	V1()
+}
+type attachment struct {
+	router fiber.Router
+	ctrl   attachmentCtrl.Controller
+}
+
+func NewAttachment(router fiber.Router) Attachment {
+	return &attachment{router: router.Group("/workspaces/:workspaceId/issues/:issueId/attachments"), ctrl: attachmentCtrl.New()}
+}
+
+func (r attachment) V1() {
+	r.root()
+}
+
+func (r attachment) root() {
+	editor := authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin, constants.WorkspaceRoleMember)
+	r.router.Post("/", authMiddleware.AccessToken, editor, r.ctrl.Create)
+	r.router.Get("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.List)
+	r.router.Post("/:attachmentId/confirm", authMiddleware.AccessToken, editor, r.ctrl.Confirm)
+	r.router.Get("/:attachmentId/download", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Download)
+	r.router.Delete("/:attachmentId", authMiddleware.AccessToken, editor, r.ctrl.Delete)
+}
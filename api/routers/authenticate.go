@@ -25,5 +25,22 @@ func (r authenticate) V1() {
 func (r authenticate) root() {
 	r.router.Post("/register", r.ctrl.Register)
 	r.router.Post("/login", r.ctrl.Login)
+	r.router.Post("/refresh", authMiddleware.RefreshToken, r.ctrl.Refresh)
+	r.router.Post("/logout", authMiddleware.AccessToken, r.ctrl.Logout)
+	r.router.Post("/logout-all", authMiddleware.AccessToken, r.ctrl.LogoutAll)
+	r.router.Get("/sessions", authMiddleware.AccessToken, r.ctrl.GetSessions)
+	r.router.Delete("/sessions/:id", authMiddleware.AccessToken, r.ctrl.RevokeSession)
 	r.router.Get("/user-info", authMiddleware.AccessToken, r.ctrl.GetUserInfo)
+	r.router.Post("/verify-email/send", authMiddleware.AccessToken, r.ctrl.SendVerificationEmail)
+	r.router.Post("/verify-email", r.ctrl.VerifyEmail)
+	r.router.Post("/password/forgot", r.ctrl.ForgotPassword)
+	r.router.Post("/password/reset", r.ctrl.ResetPassword)
+	r.router.Post("/mfa/verify", r.ctrl.VerifyMfa)
+	r.router.Post("/mfa/enroll", authMiddleware.AccessToken, r.ctrl.EnrollMfa)
+	r.router.Post("/mfa/enable", authMiddleware.AccessToken, r.ctrl.EnableMfa)
+	r.router.Post("/mfa/disable", authMiddleware.AccessToken, r.ctrl.DisableMfa)
+	r.router.Post("/mfa/recovery-codes", authMiddleware.AccessToken, r.ctrl.RegenerateMfaRecoveryCodes)
+	r.router.Get("/sso/providers", r.ctrl.GetSsoProviders)
+	r.router.Post("/sso/:provider/authorize", r.ctrl.AuthorizeSso)
+	r.router.Post("/sso/:provider/callback", r.ctrl.CallbackSso)
 }
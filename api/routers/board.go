@@ -0,0 +1,29 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	boardCtrl "jira-clone-api/api/controllers/board"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type Board interface {
+	V1()
+}
+type board struct {
+	router fiber.Router
+	ctrl   boardCtrl.Controller
+}
+
+func NewBoard(router fiber.Router) Board {
+	return &board{router: router.Group("/workspaces/:workspaceId/board"), ctrl: boardCtrl.New()}
+}
+
+func (r board) V1() {
+	r.root()
+}
+
+func (r board) root() {
+	r.router.Get("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Get)
+	r.router.Post("/issues/:issueId/move", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin, constants.WorkspaceRoleMember), r.ctrl.Move)
+}
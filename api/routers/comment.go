@@ -0,0 +1,32 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	commentCtrl "jira-clone-api/api/controllers/comment"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type Comment interface {
+	V1()
+}
+type comment struct {
+	router fiber.Router
+	ctrl   commentCtrl.Controller
+}
+
+func NewComment(router fiber.Router) Comment {
+	return &comment{router: router.Group("/workspaces/:workspaceId/issues/:issueId/comments"), ctrl: commentCtrl.New()}
+}
+
+func (r comment) V1() {
+	r.root()
+}
+
+func (r comment) root() {
+	editor := authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin, constants.WorkspaceRoleMember)
+	r.router.Post("/", authMiddleware.AccessToken, editor, r.ctrl.Create)
+	r.router.Get("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.List)
+	r.router.Patch("/:commentId", authMiddleware.AccessToken, editor, r.ctrl.Update)
+	r.router.Delete("/:commentId", authMiddleware.AccessToken, editor, r.ctrl.Delete)
+}
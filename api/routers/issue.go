@@ -0,0 +1,34 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	issueCtrl "jira-clone-api/api/controllers/issue"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type Issue interface {
+	V1()
+}
+type issue struct {
+	router fiber.Router
+	ctrl   issueCtrl.Controller
+}
+
+func NewIssue(router fiber.Router) Issue {
+	return &issue{router: router.Group("/workspaces/:workspaceId/issues"), ctrl: issueCtrl.New()}
+}
+
+func (r issue) V1() {
+	r.root()
+}
+
+func (r issue) root() {
+	editor := authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin, constants.WorkspaceRoleMember)
+	r.router.Post("/", authMiddleware.AccessToken, editor, r.ctrl.Create)
+	r.router.Get("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Search)
+	r.router.Post("/search", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.SearchJQL)
+	r.router.Get("/:issueId", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Get)
+	r.router.Patch("/:issueId", authMiddleware.AccessToken, editor, r.ctrl.Update)
+	r.router.Delete("/:issueId", authMiddleware.AccessToken, editor, r.ctrl.Delete)
+}
@@ -0,0 +1,33 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	projectCtrl "jira-clone-api/api/controllers/project"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type Project interface {
+	V1()
+}
+type project struct {
+	router fiber.Router
+	ctrl   projectCtrl.Controller
+}
+
+func NewProject(router fiber.Router) Project {
+	return &project{router: router.Group("/workspaces/:workspaceId/projects"), ctrl: projectCtrl.New()}
+}
+
+func (r project) V1() {
+	r.root()
+}
+
+func (r project) root() {
+	manager := authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin)
+	r.router.Post("/", authMiddleware.AccessToken, manager, r.ctrl.Create)
+	r.router.Get("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Search)
+	r.router.Get("/:projectId", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Get)
+	r.router.Patch("/:projectId", authMiddleware.AccessToken, manager, r.ctrl.Update)
+	r.router.Delete("/:projectId", authMiddleware.AccessToken, manager, r.ctrl.Delete)
+}
@@ -0,0 +1,32 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	savedFilterCtrl "jira-clone-api/api/controllers/saved_filter"
+	authMiddleware "jira-clone-api/api/middlewares"
+)
+
+type SavedFilter interface {
+	V1()
+}
+type savedFilter struct {
+	router fiber.Router
+	ctrl   savedFilterCtrl.Controller
+}
+
+func NewSavedFilter(router fiber.Router) SavedFilter {
+	return &savedFilter{router: router.Group("/workspaces/:workspaceId/filters"), ctrl: savedFilterCtrl.New()}
+}
+
+func (r savedFilter) V1() {
+	r.root()
+}
+
+func (r savedFilter) root() {
+	member := authMiddleware.RequireWorkspaceRole()
+	r.router.Post("/", authMiddleware.AccessToken, member, r.ctrl.Create)
+	r.router.Get("/", authMiddleware.AccessToken, member, r.ctrl.Search)
+	r.router.Get("/:filterId", authMiddleware.AccessToken, member, r.ctrl.Get)
+	r.router.Patch("/:filterId", authMiddleware.AccessToken, member, r.ctrl.Update)
+	r.router.Delete("/:filterId", authMiddleware.AccessToken, member, r.ctrl.Delete)
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	searchCtrl "jira-clone-api/api/controllers/search"
+	authMiddleware "jira-clone-api/api/middlewares"
+)
+
+type Search interface {
+	V1()
+}
+type search struct {
+	router fiber.Router
+	ctrl   searchCtrl.Controller
+}
+
+func NewSearch(router fiber.Router) Search {
+	return &search{router: router.Group("/search"), ctrl: searchCtrl.New()}
+}
+
+func (r search) V1() {
+	r.root()
+}
+
+func (r search) root() {
+	r.router.Get("/", authMiddleware.AccessToken, r.ctrl.Search)
+}
@@ -0,0 +1,37 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	sprintCtrl "jira-clone-api/api/controllers/sprint"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type Sprint interface {
+	V1()
+}
+type sprint struct {
+	router fiber.Router
+	ctrl   sprintCtrl.Controller
+}
+
+func NewSprint(router fiber.Router) Sprint {
+	return &sprint{router: router.Group("/workspaces/:workspaceId/sprints"), ctrl: sprintCtrl.New()}
+}
+
+func (r sprint) V1() {
+	r.root()
+}
+
+func (r sprint) root() {
+	editor := authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin, constants.WorkspaceRoleMember)
+	r.router.Post("/", authMiddleware.AccessToken, editor, r.ctrl.Create)
+	r.router.Get("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Search)
+	r.router.Get("/:sprintId", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Get)
+	r.router.Patch("/:sprintId", authMiddleware.AccessToken, editor, r.ctrl.Update)
+	r.router.Delete("/:sprintId", authMiddleware.AccessToken, editor, r.ctrl.Delete)
+	r.router.Post("/:sprintId/issues", authMiddleware.AccessToken, editor, r.ctrl.AddIssues)
+	r.router.Delete("/:sprintId/issues", authMiddleware.AccessToken, editor, r.ctrl.RemoveIssues)
+	r.router.Post("/:sprintId/start", authMiddleware.AccessToken, editor, r.ctrl.Start)
+	r.router.Post("/:sprintId/complete", authMiddleware.AccessToken, editor, r.ctrl.Complete)
+}
@@ -0,0 +1,33 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	storageCtrl "jira-clone-api/api/controllers/storage"
+	"jira-clone-api/common/configure"
+	"jira-clone-api/utilities/storage"
+)
+
+type Storage interface {
+	V1()
+}
+type storageRouter struct {
+	router fiber.Router
+	ctrl   storageCtrl.Controller
+}
+
+func NewStorage(router fiber.Router) Storage {
+	return &storageRouter{router: router.Group("/storage/objects"), ctrl: storageCtrl.New()}
+}
+
+// V1 only mounts the routes for drivers that hand out URLs to them; S3 serves its own pre-signed URLs.
+func (r storageRouter) V1() {
+	if configure.GetConfig().StorageDriver == storage.DriverS3 {
+		return
+	}
+	r.root()
+}
+
+func (r storageRouter) root() {
+	r.router.Get("/*", r.ctrl.Download)
+	r.router.Put("/*", r.ctrl.Upload)
+}
@@ -0,0 +1,29 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	workflowCtrl "jira-clone-api/api/controllers/workflow"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type Workflow interface {
+	V1()
+}
+type workflow struct {
+	router fiber.Router
+	ctrl   workflowCtrl.Controller
+}
+
+func NewWorkflow(router fiber.Router) Workflow {
+	return &workflow{router: router.Group("/workspaces/:workspaceId/workflow"), ctrl: workflowCtrl.New()}
+}
+
+func (r workflow) V1() {
+	r.root()
+}
+
+func (r workflow) root() {
+	r.router.Get("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Get)
+	r.router.Put("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin), r.ctrl.Update)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	workspaceCtrl "jira-clone-api/api/controllers/workspace"
 	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
 )
 
 type Workspace interface {
@@ -25,4 +26,8 @@ func (r workspace) V1() {
 func (r workspace) root() {
 	r.router.Post("/", authMiddleware.AccessToken, r.ctrl.Create)
 	r.router.Get("/", authMiddleware.AccessToken, r.ctrl.Search)
+	r.router.Get("/:workspaceId", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Get)
+	r.router.Patch("/:workspaceId", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin), r.ctrl.Update)
+	r.router.Delete("/:workspaceId", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner), r.ctrl.Delete)
+	r.router.Post("/:workspaceId/restore", authMiddleware.AccessToken, r.ctrl.Restore)
 }
@@ -0,0 +1,38 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	workspaceInvitationCtrl "jira-clone-api/api/controllers/workspace_invitation"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type WorkspaceInvitation interface {
+	V1()
+}
+type workspaceInvitation struct {
+	router fiber.Router
+	ctrl   workspaceInvitationCtrl.Controller
+}
+
+func NewWorkspaceInvitation(router fiber.Router) WorkspaceInvitation {
+	return &workspaceInvitation{router: router, ctrl: workspaceInvitationCtrl.New()}
+}
+
+func (r workspaceInvitation) V1() {
+	r.root()
+	r.workspace()
+}
+
+func (r workspaceInvitation) root() {
+	router := r.router.Group("/invitations")
+	router.Post("/accept", authMiddleware.AccessToken, r.ctrl.Accept)
+}
+
+func (r workspaceInvitation) workspace() {
+	router := r.router.Group("/workspaces/:workspaceId/invitations")
+	manager := authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin)
+	router.Post("/", authMiddleware.AccessToken, manager, r.ctrl.Create)
+	router.Get("/", authMiddleware.AccessToken, manager, r.ctrl.Search)
+	router.Delete("/:invitationId", authMiddleware.AccessToken, manager, r.ctrl.Revoke)
+}
@@ -0,0 +1,32 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	workspaceMemberCtrl "jira-clone-api/api/controllers/workspace_member"
+	authMiddleware "jira-clone-api/api/middlewares"
+	"jira-clone-api/common/constants"
+)
+
+type WorkspaceMember interface {
+	V1()
+}
+type workspaceMember struct {
+	router fiber.Router
+	ctrl   workspaceMemberCtrl.Controller
+}
+
+func NewWorkspaceMember(router fiber.Router) WorkspaceMember {
+	return &workspaceMember{router: router.Group("/workspaces/:workspaceId/members"), ctrl: workspaceMemberCtrl.New()}
+}
+
+func (r workspaceMember) V1() {
+	r.root()
+}
+
+func (r workspaceMember) root() {
+	manager := authMiddleware.RequireWorkspaceRole(constants.WorkspaceRoleOwner, constants.WorkspaceRoleAdmin)
+	r.router.Post("/", authMiddleware.AccessToken, manager, r.ctrl.Add)
+	r.router.Get("/", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Search)
+	r.router.Patch("/:userId", authMiddleware.AccessToken, manager, r.ctrl.UpdateRole)
+	r.router.Delete("/:userId", authMiddleware.AccessToken, authMiddleware.RequireWorkspaceRole(), r.ctrl.Remove)
+}
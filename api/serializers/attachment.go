@@ -0,0 +1,49 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type AttachmentCreateBodyValidate struct {
+	Filename string `json:"filename" validate:"required,max=255"`
+	Size     int64  `json:"size" validate:"required,min=1"`
+}
+
+func (v *AttachmentCreateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+// AttachmentUploadResponse tells the client how to upload the file straight to storage.
+// The request must use Method and carry Headers unchanged, otherwise the signature is rejected.
+type AttachmentUploadResponse struct {
+	ExpiresAt time.Time          `json:"expires_at"`
+	UploadUrl string             `json:"upload_url"`
+	Method    string             `json:"method"`
+	Headers   map[string]string  `json:"headers"`
+	Id        primitive.ObjectID `json:"id"`
+}
+
+type AttachmentResponse struct {
+	CreatedAt   time.Time          `json:"created_at"`
+	Filename    string             `json:"filename"`
+	ContentType string             `json:"content_type"`
+	Size        int64              `json:"size"`
+	UploadedBy  primitive.ObjectID `json:"uploaded_by"`
+	Id          primitive.ObjectID `json:"id"`
+}
+
+type AttachmentDownloadResponse struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	Url       string    `json:"url"`
+}
@@ -1,6 +1,8 @@
 package serializers
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"jira-clone-api/common/request/validator"
@@ -10,7 +12,7 @@ import (
 type AuthenticateRegisterBodyValidate struct {
 	Username string `json:"username" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Password string `json:"password" validate:"required,password"`
 }
 
 func (v *AuthenticateRegisterBodyValidate) Validate() error {
@@ -38,13 +40,148 @@ func (v *AuthenticateLoginBodyValidate) Validate() error {
 	return nil
 }
 
+type AuthenticateVerifyEmailBodyValidate struct {
+	Token string `json:"token" validate:"required,max=128"`
+}
+
+func (v *AuthenticateVerifyEmailBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type AuthenticateForgotPasswordBodyValidate struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (v *AuthenticateForgotPasswordBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type AuthenticateResetPasswordBodyValidate struct {
+	Token    string `json:"token" validate:"required,max=128"`
+	Password string `json:"password" validate:"required,password"`
+}
+
+func (v *AuthenticateResetPasswordBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type AuthenticateMfaCodeBodyValidate struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+func (v *AuthenticateMfaCodeBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type AuthenticateMfaVerifyBodyValidate struct {
+	MfaToken string `json:"mfa_token" validate:"required,max=1024"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
+
+func (v *AuthenticateMfaVerifyBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type AuthenticateMfaDisableBodyValidate struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
+
+func (v *AuthenticateMfaDisableBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type AuthenticateSsoCallbackBodyValidate struct {
+	Code  string `json:"code" validate:"required,max=2048"`
+	State string `json:"state" validate:"required,max=128"`
+}
+
+func (v *AuthenticateSsoCallbackBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
 type AuthenticateLoginResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+}
+
+// AuthenticateMfaPendingResponse is what Login answers instead of a token pair when a second factor
+// is required; MfaToken is exchanged for the pair at /auth/mfa/verify.
+type AuthenticateMfaPendingResponse struct {
+	MfaRequired bool   `json:"mfa_required"`
+	MfaToken    string `json:"mfa_token"`
+}
+
+type AuthenticateMfaEnrollResponse struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+type AuthenticateMfaRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type AuthenticateSsoAuthorizeResponse struct {
+	AuthorizationUrl string `json:"authorization_url"`
+	State            string `json:"state"`
 }
 
 type AuthenticateGetUserInfoResponse struct {
-	Username string             `json:"username"`
-	Email    string             `json:"email"`
-	Id       primitive.ObjectID `json:"id"`
+	Username      string             `json:"username"`
+	Email         string             `json:"email"`
+	EmailVerified bool               `json:"email_verified"`
+	MfaEnabled    bool               `json:"mfa_enabled"`
+	Id            primitive.ObjectID `json:"id"`
+}
+
+type AuthenticateSessionResponseItem struct {
+	CreatedAt  time.Time          `json:"created_at"`
+	LastUsedAt time.Time          `json:"last_used_at"`
+	UserAgent  string             `json:"user_agent"`
+	IpAddress  string             `json:"ip_address"`
+	Id         primitive.ObjectID `json:"id"`
+	IsCurrent  bool               `json:"is_current"`
 }
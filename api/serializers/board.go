@@ -0,0 +1,60 @@
+package serializers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type BoardQueryValidate struct {
+	ProjectId  string `query:"project_id" validate:"omitempty,mongodb"`
+	AssigneeId string `query:"assignee_id" validate:"omitempty,mongodb"`
+}
+
+func (v *BoardQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+// BoardMoveBodyValidate places an issue between two cards of the target column.
+// PrevId is the card right above the drop position and NextId the card right below it;
+// leaving one out means the top or the bottom of the column.
+type BoardMoveBodyValidate struct {
+	Status string             `json:"status" validate:"omitempty,max=50"`
+	PrevId primitive.ObjectID `json:"prev_id" validate:"omitempty"`
+	NextId primitive.ObjectID `json:"next_id" validate:"omitempty"`
+}
+
+func (v *BoardMoveBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type BoardCardResponse struct {
+	Key        string             `json:"key"`
+	Title      string             `json:"title"`
+	Status     string             `json:"status"`
+	Priority   string             `json:"priority"`
+	Rank       string             `json:"rank"`
+	Labels     []string           `json:"labels"`
+	ProjectId  primitive.ObjectID `json:"project_id"`
+	AssigneeId primitive.ObjectID `json:"assignee_id"`
+	Id         primitive.ObjectID `json:"id"`
+}
+
+type BoardColumnResponse struct {
+	Status   string              `json:"status"`
+	Category string              `json:"category"`
+	Issues   []BoardCardResponse `json:"issues"`
+}
@@ -0,0 +1,69 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type CommentCreateBodyValidate struct {
+	Body     string             `json:"body" validate:"required,max=20000"`
+	ParentId primitive.ObjectID `json:"parent_id" validate:"omitempty"`
+}
+
+func (v *CommentCreateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type CommentUpdateBodyValidate struct {
+	Body string `json:"body" validate:"required,max=20000"`
+}
+
+func (v *CommentUpdateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+// CommentListQueryValidate pages through comments with a cursor: After is the id of the last
+// comment already received, so comments posted meanwhile never shift the pages.
+type CommentListQueryValidate struct {
+	ParentId string `query:"parent_id" validate:"omitempty,mongodb"`
+	After    string `query:"after" validate:"omitempty,mongodb"`
+	Limit    int64  `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (v *CommentListQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type CommentResponse struct {
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+	EditedAt   *time.Time           `json:"edited_at"`
+	Body       string               `json:"body"`
+	Mentions   []primitive.ObjectID `json:"mentions"`
+	ReplyCount int64                `json:"reply_count"`
+	ParentId   primitive.ObjectID   `json:"parent_id"`
+	AuthorId   primitive.ObjectID   `json:"author_id"`
+	Id         primitive.ObjectID   `json:"id"`
+}
@@ -0,0 +1,111 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type IssueCreateBodyValidate struct {
+	DueDate     *time.Time         `json:"due_date" validate:"omitempty"`
+	Title       string             `json:"title" validate:"required,max=255"`
+	Description string             `json:"description" validate:"omitempty,max=50000"`
+	Status      string             `json:"status" validate:"omitempty,max=50"`
+	Priority    string             `json:"priority" validate:"omitempty,oneof=lowest low medium high highest"`
+	Labels      []string           `json:"labels" validate:"omitempty,max=20,dive,min=1,max=50"`
+	ProjectId   primitive.ObjectID `json:"project_id" validate:"required"`
+	AssigneeId  primitive.ObjectID `json:"assignee_id" validate:"omitempty"`
+}
+
+func (v *IssueCreateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type IssueUpdateBodyValidate struct {
+	DueDate     *time.Time          `json:"due_date" validate:"omitempty"`
+	Title       *string             `json:"title" validate:"omitempty,min=1,max=255"`
+	Description *string             `json:"description" validate:"omitempty,max=50000"`
+	Status      *string             `json:"status" validate:"omitempty,min=1,max=50"`
+	Priority    *string             `json:"priority" validate:"omitempty,oneof=lowest low medium high highest"`
+	Labels      *[]string           `json:"labels" validate:"omitempty,max=20,dive,min=1,max=50"`
+	AssigneeId  *primitive.ObjectID `json:"assignee_id" validate:"omitempty"`
+}
+
+func (v *IssueUpdateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type IssueSearchQueryValidate struct {
+	Title      string   `query:"title" validate:"omitempty"`
+	Status     string   `query:"status" validate:"omitempty"`
+	Category   string   `query:"category" validate:"omitempty,oneof=todo in_progress done"`
+	Priority   string   `query:"priority" validate:"omitempty,oneof=lowest low medium high highest"`
+	ProjectId  string   `query:"project_id" validate:"omitempty,mongodb"`
+	AssigneeId string   `query:"assignee_id" validate:"omitempty,mongodb"`
+	SprintId   string   `query:"sprint_id" validate:"omitempty,mongodb"`
+	Backlog    bool     `query:"backlog" validate:"omitempty"`
+	Filter     []string `query:"filter" validate:"omitempty"`
+	Sort       string   `query:"sort" validate:"omitempty"`
+	Page       int64    `query:"page" validate:"omitempty"`
+	Limit      int64    `query:"limit" validate:"omitempty"`
+}
+
+func (v *IssueSearchQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+// IssueJQLSearchBodyValidate runs either an ad-hoc query or a saved filter of the caller.
+type IssueJQLSearchBodyValidate struct {
+	JQL      string `json:"jql" validate:"required_without=FilterId,max=4096"`
+	FilterId string `json:"filter_id" validate:"omitempty,mongodb"`
+	Page     int64  `json:"page" validate:"omitempty"`
+	Limit    int64  `json:"limit" validate:"omitempty"`
+}
+
+func (v *IssueJQLSearchBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type IssueResponse struct {
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	DueDate     *time.Time         `json:"due_date"`
+	Key         string             `json:"key"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Status      string             `json:"status"`
+	Priority    string             `json:"priority"`
+	Labels      []string           `json:"labels"`
+	ProjectId   primitive.ObjectID `json:"project_id"`
+	AssigneeId  primitive.ObjectID `json:"assignee_id"`
+	SprintId    primitive.ObjectID `json:"sprint_id"`
+	ReporterId  primitive.ObjectID `json:"reporter_id"`
+	Id          primitive.ObjectID `json:"id"`
+}
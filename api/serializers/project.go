@@ -0,0 +1,74 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type ProjectCreateBodyValidate struct {
+	Name        string             `json:"name" validate:"required,max=100"`
+	Key         string             `json:"key" validate:"required,project_key"`
+	Description string             `json:"description" validate:"omitempty,max=10000"`
+	Icon        string             `json:"icon" validate:"omitempty,max=255"`
+	LeadId      primitive.ObjectID `json:"lead_id" validate:"omitempty"`
+}
+
+func (v *ProjectCreateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type ProjectUpdateBodyValidate struct {
+	Name        *string             `json:"name" validate:"omitempty,min=1,max=100"`
+	Description *string             `json:"description" validate:"omitempty,max=10000"`
+	Icon        *string             `json:"icon" validate:"omitempty,max=255"`
+	LeadId      *primitive.ObjectID `json:"lead_id" validate:"omitempty"`
+}
+
+func (v *ProjectUpdateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type ProjectSearchQueryValidate struct {
+	Name   string   `query:"name" validate:"omitempty"`
+	Filter []string `query:"filter" validate:"omitempty"`
+	Sort   string   `query:"sort" validate:"omitempty"`
+	Page   int64    `query:"page" validate:"omitempty"`
+	Limit  int64    `query:"limit" validate:"omitempty"`
+}
+
+func (v *ProjectSearchQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type ProjectResponse struct {
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	Name        string             `json:"name"`
+	Key         string             `json:"key"`
+	Description string             `json:"description"`
+	Icon        string             `json:"icon"`
+	LeadId      primitive.ObjectID `json:"lead_id"`
+	Id          primitive.ObjectID `json:"id"`
+}
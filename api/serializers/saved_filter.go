@@ -0,0 +1,63 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type SavedFilterCreateBodyValidate struct {
+	Name string `json:"name" validate:"required,max=255"`
+	JQL  string `json:"jql" validate:"required,max=4096"`
+}
+
+func (v *SavedFilterCreateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SavedFilterSearchQueryValidate struct {
+	Page  int64 `query:"page" validate:"omitempty"`
+	Limit int64 `query:"limit" validate:"omitempty"`
+}
+
+func (v *SavedFilterSearchQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SavedFilterUpdateBodyValidate struct {
+	Name string `json:"name" validate:"omitempty,max=255"`
+	JQL  string `json:"jql" validate:"omitempty,max=4096"`
+}
+
+func (v *SavedFilterUpdateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SavedFilterResponse struct {
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	Name      string             `json:"name"`
+	JQL       string             `json:"jql"`
+	Id        primitive.ObjectID `json:"id"`
+}
@@ -0,0 +1,37 @@
+package serializers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type SearchQueryValidate struct {
+	Query       string   `query:"q" validate:"required,max=200"`
+	WorkspaceId string   `query:"workspace_id" validate:"omitempty,mongodb"`
+	Types       []string `query:"types" validate:"omitempty,max=4,dive,oneof=workspace project issue comment"`
+	Limit       int64    `query:"limit" validate:"omitempty,min=1,max=50"`
+}
+
+func (v *SearchQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SearchResponseItem struct {
+	Type        string              `json:"type"`
+	Score       float64             `json:"score"`
+	Title       string              `json:"title"`
+	Snippet     string              `json:"snippet"`
+	Key         string              `json:"key,omitempty"`
+	WorkspaceId primitive.ObjectID  `json:"workspace_id"`
+	ProjectId   *primitive.ObjectID `json:"project_id,omitempty"`
+	IssueId     *primitive.ObjectID `json:"issue_id,omitempty"`
+	Id          primitive.ObjectID  `json:"id"`
+}
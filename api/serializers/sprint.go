@@ -0,0 +1,127 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type SprintCreateBodyValidate struct {
+	StartDate *time.Time         `json:"start_date" validate:"omitempty"`
+	EndDate   *time.Time         `json:"end_date" validate:"omitempty"`
+	Name      string             `json:"name" validate:"required,max=100"`
+	Goal      string             `json:"goal" validate:"omitempty,max=1000"`
+	ProjectId primitive.ObjectID `json:"project_id" validate:"omitempty"`
+}
+
+func (v *SprintCreateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SprintUpdateBodyValidate struct {
+	StartDate *time.Time `json:"start_date" validate:"omitempty"`
+	EndDate   *time.Time `json:"end_date" validate:"omitempty"`
+	Name      *string    `json:"name" validate:"omitempty,min=1,max=100"`
+	Goal      *string    `json:"goal" validate:"omitempty,max=1000"`
+}
+
+func (v *SprintUpdateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SprintSearchQueryValidate struct {
+	State     string   `query:"state" validate:"omitempty,oneof=planned active closed"`
+	ProjectId string   `query:"project_id" validate:"omitempty,mongodb"`
+	Filter    []string `query:"filter" validate:"omitempty"`
+	Sort      string   `query:"sort" validate:"omitempty"`
+	Page      int64    `query:"page" validate:"omitempty"`
+	Limit     int64    `query:"limit" validate:"omitempty"`
+}
+
+func (v *SprintSearchQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SprintIssuesBodyValidate struct {
+	IssueIds []primitive.ObjectID `json:"issue_ids" validate:"required,min=1,max=100"`
+}
+
+func (v *SprintIssuesBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SprintStartBodyValidate struct {
+	StartDate *time.Time `json:"start_date" validate:"omitempty"`
+	EndDate   *time.Time `json:"end_date" validate:"omitempty"`
+}
+
+func (v *SprintStartBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+// SprintCompleteBodyValidate moves unfinished issues to NextSprintId, or to the backlog when it is empty.
+type SprintCompleteBodyValidate struct {
+	NextSprintId primitive.ObjectID `json:"next_sprint_id" validate:"omitempty"`
+}
+
+func (v *SprintCompleteBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type SprintResponse struct {
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	StartDate   *time.Time         `json:"start_date"`
+	EndDate     *time.Time         `json:"end_date"`
+	CompletedAt *time.Time         `json:"completed_at"`
+	Name        string             `json:"name"`
+	Goal        string             `json:"goal"`
+	State       string             `json:"state"`
+	ProjectId   primitive.ObjectID `json:"project_id"`
+	Id          primitive.ObjectID `json:"id"`
+}
+
+type SprintCompleteResponse struct {
+	Sprint       SprintResponse     `json:"sprint"`
+	MovedIssues  int64              `json:"moved_issues"`
+	NextSprintId primitive.ObjectID `json:"next_sprint_id"`
+}
@@ -0,0 +1,42 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type WorkflowStatusItem struct {
+	Name     string `json:"name" validate:"required,max=50"`
+	Category string `json:"category" validate:"required,oneof=todo in_progress done"`
+}
+
+type WorkflowTransitionItem struct {
+	From string `json:"from" validate:"required,max=50"`
+	To   string `json:"to" validate:"required,max=50"`
+}
+
+type WorkflowUpdateBodyValidate struct {
+	InitialStatus string                   `json:"initial_status" validate:"required,max=50"`
+	Statuses      []WorkflowStatusItem     `json:"statuses" validate:"required,min=1,max=50,dive"`
+	Transitions   []WorkflowTransitionItem `json:"transitions" validate:"omitempty,max=500,dive"`
+}
+
+func (v *WorkflowUpdateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type WorkflowResponse struct {
+	UpdatedAt     time.Time                `json:"updated_at"`
+	InitialStatus string                   `json:"initial_status"`
+	Statuses      []WorkflowStatusItem     `json:"statuses"`
+	Transitions   []WorkflowTransitionItem `json:"transitions"`
+}
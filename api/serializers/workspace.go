@@ -24,9 +24,11 @@ func (v *WorkspaceCreateBodyValidate) Validate() error {
 }
 
 type WorkspaceSearchBodyValidate struct {
-	Name  string `json:"name" validate:"omitempty"`
-	Page  int64  `json:"page" validate:"omitempty"`
-	Limit int64  `json:"limit" validate:"omitempty"`
+	Name   string   `json:"name" validate:"omitempty"`
+	Filter []string `json:"filter" validate:"omitempty"`
+	Sort   string   `json:"sort" validate:"omitempty"`
+	Page   int64    `json:"page" validate:"omitempty"`
+	Limit  int64    `json:"limit" validate:"omitempty"`
 }
 
 func (v *WorkspaceSearchBodyValidate) Validate() error {
@@ -42,7 +44,35 @@ func (v *WorkspaceSearchBodyValidate) Validate() error {
 type WorkspaceSearchResponseItem struct {
 	CreatedAt time.Time          `json:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at"`
-	ImageUrl  string             `json:"image_url"`
+	ImageUrls map[string]string  `json:"image_urls"`
 	Name      string             `json:"name"`
+	Role      string             `json:"role"`
 	Id        primitive.ObjectID `json:"id"`
 }
+
+type WorkspaceGetResponse struct {
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	ImageUrls map[string]string  `json:"image_urls"`
+	Name      string             `json:"name"`
+	Role      string             `json:"role"`
+	Id        primitive.ObjectID `json:"id"`
+}
+
+type WorkspaceUpdateBodyValidate struct {
+	Name string `form:"name" validate:"omitempty"`
+}
+
+func (v *WorkspaceUpdateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type WorkspaceDeleteResponse struct {
+	RestorableUntil time.Time `json:"restorable_until"`
+}
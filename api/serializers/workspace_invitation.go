@@ -0,0 +1,76 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type WorkspaceInvitationCreateBodyValidate struct {
+	Email string `json:"email" validate:"omitempty,email"`
+	Role  string `json:"role" validate:"required,oneof=admin member viewer"`
+}
+
+func (v *WorkspaceInvitationCreateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type WorkspaceInvitationCreateResponse struct {
+	ExpiredAt time.Time          `json:"expired_at"`
+	Code      string             `json:"code"`
+	Id        primitive.ObjectID `json:"id"`
+}
+
+type WorkspaceInvitationSearchQueryValidate struct {
+	Filter []string `query:"filter" validate:"omitempty"`
+	Sort   string   `query:"sort" validate:"omitempty"`
+	Page   int64    `query:"page" validate:"omitempty"`
+	Limit  int64    `query:"limit" validate:"omitempty"`
+}
+
+func (v *WorkspaceInvitationSearchQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type WorkspaceInvitationResponseItem struct {
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiredAt time.Time          `json:"expired_at"`
+	Email     string             `json:"email"`
+	Role      string             `json:"role"`
+	CreatedBy primitive.ObjectID `json:"created_by"`
+	Id        primitive.ObjectID `json:"id"`
+}
+
+type WorkspaceInvitationAcceptBodyValidate struct {
+	Code string `json:"code" validate:"required"`
+}
+
+func (v *WorkspaceInvitationAcceptBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type WorkspaceInvitationAcceptResponse struct {
+	Role        string             `json:"role"`
+	WorkspaceId primitive.ObjectID `json:"workspace_id"`
+}
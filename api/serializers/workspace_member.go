@@ -0,0 +1,64 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/request/validator"
+	"jira-clone-api/common/response"
+)
+
+type WorkspaceMemberAddBodyValidate struct {
+	Username string `json:"username" validate:"required"`
+	Role     string `json:"role" validate:"required,oneof=owner admin member viewer"`
+}
+
+func (v *WorkspaceMemberAddBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type WorkspaceMemberUpdateBodyValidate struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member viewer"`
+}
+
+func (v *WorkspaceMemberUpdateBodyValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type WorkspaceMemberSearchQueryValidate struct {
+	Filter []string `query:"filter" validate:"omitempty"`
+	Sort   string   `query:"sort" validate:"omitempty"`
+	Page   int64    `query:"page" validate:"omitempty"`
+	Limit  int64    `query:"limit" validate:"omitempty"`
+}
+
+func (v *WorkspaceMemberSearchQueryValidate) Validate() error {
+	validateEngine := validator.GetValidateEngine()
+	if err := validateEngine.Struct(v); err != nil {
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data: validator.ParseValidateError(err),
+		})
+	}
+	return nil
+}
+
+type WorkspaceMemberResponseItem struct {
+	JoinedAt time.Time          `json:"joined_at"`
+	Username string             `json:"username"`
+	Email    string             `json:"email"`
+	Role     string             `json:"role"`
+	UserId   primitive.ObjectID `json:"user_id"`
+}
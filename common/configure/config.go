@@ -11,28 +11,84 @@ import (
 
 var config *Configuration
 
+// SsoProvider configures one single sign-on provider, which is enabled once ClientId is set.
+// Issuer is the OIDC issuer discovery starts from; Google has a fixed one and GitHub is plain OAuth2.
+type SsoProvider struct {
+	Issuer       string   `env:"ISSUER"`
+	ClientId     string   `env:"CLIENT_ID"`
+	ClientSecret string   `env:"CLIENT_SECRET"`
+	Scopes       []string `env:"SCOPES" envSeparator:" "`
+}
+
 type Configuration struct {
-	Host                  string        `env:"HOST" envDefault:"0.0.0.0"`
-	Port                  string        `env:"PORT" envDefault:"8080"`
-	TokenType             string        `env:"TOKEN_TYPE" envDefault:"Bearer"`
-	TokenPublicKey        string        `env:"TOKEN_PUBLIC_KEY_PATH,file" envDefault:"certs/public.pem" envExpand:"true"`
-	TokenPrivateKey       string        `env:"TOKEN_PRIVATE_KEY_PATH,file" envDefault:"certs/private.pem" envExpand:"true"`
-	MongoDBJiraUri        string        `env:"MONGODB_JIRA_URI" envDefault:"mongodb://localhost:27017"`
-	MongoDBJiraName       string        `env:"MONGODB_JIRA_NAME" envDefault:"db_jira"`
-	S3AccessKeyId         string        `env:"S3_ACCESS_KEY_ID" envDefault:"!change_me!"`
-	S3SecretAccessKey     string        `env:"S3_SECRET_ACCESS_KEY" envDefault:"!change_me!"`
-	S3Region              string        `env:"S3_REGION" envDefault:"!change_me!"`
-	S3Endpoint            string        `env:"S3_ENDPOINT_URL" envDefault:"localhost:9000"`
-	S3BucketName          string        `env:"S3_BUCKET_NAME" envDefault:"jira"`
-	S3Prefix              string        `env:"S3_PREFIX" envDefault:"http://localhost:9001/jira"`
-	MongoDBRequestTimeout time.Duration `env:"MONGODB_REQUEST_TIMEOUT" envDefault:"3m"`
-	AccessTokenTimeout    time.Duration `env:"ACCESS_TOKEN_TIMEOUT" envDefault:"1h"`
-	RefreshTokenTimeout   time.Duration `env:"REFRESH_TOKEN_TIMEOUT" envDefault:"2h"`
-	PaginationMaxItem     int64         `env:"PAGINATION_MAX_ITEM" envDefault:"50"`
-	APIBodyLimitSize      int           `env:"API_BODY_LIMIT_SIZE" envDefault:"1073741824"`
-	Debug                 bool          `env:"DEBUG" envDefault:"true"`
-	ElasticAPMEnable      bool          `env:"ELASTIC_APM_ENABLE" envDefault:"false"`
-	MongoAutoIndexing     bool          `env:"MONGO_AUTO_INDEXING" envDefault:"true"`
+	Host                      string        `env:"HOST" envDefault:"0.0.0.0"`
+	Port                      string        `env:"PORT" envDefault:"8080"`
+	TokenType                 string        `env:"TOKEN_TYPE" envDefault:"Bearer"`
+	TokenPublicKey            string        `env:"TOKEN_PUBLIC_KEY_PATH,file" envDefault:"certs/public.pem" envExpand:"true"`
+	TokenPrivateKey           string        `env:"TOKEN_PRIVATE_KEY_PATH,file" envDefault:"certs/private.pem" envExpand:"true"`
+	MongoDBJiraUri            string        `env:"MONGODB_JIRA_URI" envDefault:"mongodb://localhost:27017/?directConnection=true"`
+	MongoDBJiraName           string        `env:"MONGODB_JIRA_NAME" envDefault:"db_jira"`
+	S3AccessKeyId             string        `env:"S3_ACCESS_KEY_ID" envDefault:"!change_me!"`
+	S3SecretAccessKey         string        `env:"S3_SECRET_ACCESS_KEY" envDefault:"!change_me!"`
+	S3Region                  string        `env:"S3_REGION" envDefault:"!change_me!"`
+	S3Endpoint                string        `env:"S3_ENDPOINT_URL" envDefault:"localhost:9000"`
+	S3BucketName              string        `env:"S3_BUCKET_NAME" envDefault:"jira"`
+	S3Prefix                  string        `env:"S3_PREFIX" envDefault:"http://localhost:9001/jira"`
+	StorageDriver             string        `env:"STORAGE_DRIVER" envDefault:"s3"`
+	StorageLocalPath          string        `env:"STORAGE_LOCAL_PATH" envDefault:"storage"`
+	StorageSigningKey         string        `env:"STORAGE_SIGNING_KEY"`
+	StoragePublicUrl          string        `env:"STORAGE_PUBLIC_URL" envDefault:"http://localhost:8080/api/jira-clone-api/v1/storage/objects"`
+	MailerDriver              string        `env:"MAILER_DRIVER" envDefault:"smtp"`
+	MailerFrom                string        `env:"MAILER_FROM" envDefault:"Jira Clone <no-reply@localhost>"`
+	MailerFilePath            string        `env:"MAILER_FILE_PATH" envDefault:"mails"`
+	SMTPHost                  string        `env:"SMTP_HOST" envDefault:"localhost"`
+	SMTPPort                  string        `env:"SMTP_PORT" envDefault:"1025"`
+	SMTPUsername              string        `env:"SMTP_USERNAME"`
+	SMTPPassword              string        `env:"SMTP_PASSWORD"`
+	WebUrl                    string        `env:"WEB_URL" envDefault:"http://localhost:3000"`
+	CorsAllowOrigins          string        `env:"CORS_ALLOW_ORIGINS" envDefault:"*"`
+	ProxyHeader               string        `env:"PROXY_HEADER"`
+	MfaIssuer                 string        `env:"MFA_ISSUER" envDefault:"Jira Clone"`
+	SsoRedirectUrl            string        `env:"SSO_REDIRECT_URL" envDefault:"http://localhost:3000/auth/sso/callback"`
+	MongoDBRequestTimeout     time.Duration `env:"MONGODB_REQUEST_TIMEOUT" envDefault:"3m"`
+	AccessTokenTimeout        time.Duration `env:"ACCESS_TOKEN_TIMEOUT" envDefault:"1h"`
+	RefreshTokenTimeout       time.Duration `env:"REFRESH_TOKEN_TIMEOUT" envDefault:"2h"`
+	InvitationTimeout         time.Duration `env:"INVITATION_TIMEOUT" envDefault:"168h"`
+	EmailVerificationTimeout  time.Duration `env:"EMAIL_VERIFICATION_TIMEOUT" envDefault:"24h"`
+	PasswordResetTimeout      time.Duration `env:"PASSWORD_RESET_TIMEOUT" envDefault:"1h"`
+	MfaPendingTokenTimeout    time.Duration `env:"MFA_PENDING_TOKEN_TIMEOUT" envDefault:"5m"`
+	SsoStateTimeout           time.Duration `env:"SSO_STATE_TIMEOUT" envDefault:"10m"`
+	LoginAttemptWindow        time.Duration `env:"LOGIN_ATTEMPT_WINDOW" envDefault:"24h"`
+	LoginLockoutBase          time.Duration `env:"LOGIN_LOCKOUT_BASE" envDefault:"1m"`
+	LoginLockoutMax           time.Duration `env:"LOGIN_LOCKOUT_MAX" envDefault:"1h"`
+	WorkspaceRestoreWindow    time.Duration `env:"WORKSPACE_RESTORE_WINDOW" envDefault:"720h"`
+	WorkspacePurgeInterval    time.Duration `env:"WORKSPACE_PURGE_INTERVAL" envDefault:"1h"`
+	AttachmentUploadTimeout   time.Duration `env:"ATTACHMENT_UPLOAD_TIMEOUT" envDefault:"15m"`
+	AttachmentDownloadTimeout time.Duration `env:"ATTACHMENT_DOWNLOAD_TIMEOUT" envDefault:"5m"`
+	AttachmentCleanupInterval time.Duration `env:"ATTACHMENT_CLEANUP_INTERVAL" envDefault:"1h"`
+	AttachmentMaxSize         int64         `env:"ATTACHMENT_MAX_SIZE" envDefault:"26214400"`
+	PaginationMaxItem         int64         `env:"PAGINATION_MAX_ITEM" envDefault:"50"`
+	LoginMaxFailures          int64         `env:"LOGIN_MAX_FAILURES" envDefault:"5"`
+	LoginIpMaxFailures        int64         `env:"LOGIN_IP_MAX_FAILURES" envDefault:"50"`
+	PasswordMinLength         int           `env:"PASSWORD_MIN_LENGTH" envDefault:"10"`
+	PasswordMaxLength         int           `env:"PASSWORD_MAX_LENGTH" envDefault:"128"`
+	PasswordArgon2Memory      uint32        `env:"PASSWORD_ARGON2_MEMORY" envDefault:"65536"`
+	PasswordArgon2Iterations  uint32        `env:"PASSWORD_ARGON2_ITERATIONS" envDefault:"3"`
+	PasswordArgon2Parallelism uint8         `env:"PASSWORD_ARGON2_PARALLELISM" envDefault:"2"`
+	APIBodyLimitSize          int           `env:"API_BODY_LIMIT_SIZE" envDefault:"1073741824"`
+	Debug                     bool          `env:"DEBUG" envDefault:"true"`
+	ElasticAPMEnable          bool          `env:"ELASTIC_APM_ENABLE" envDefault:"false"`
+	MongoAutoIndexing         bool          `env:"MONGO_AUTO_INDEXING" envDefault:"true"`
+	MongoAutoMigration        bool          `env:"MONGO_AUTO_MIGRATION" envDefault:"true"`
+	RequireVerifiedEmail      bool          `env:"REQUIRE_VERIFIED_EMAIL" envDefault:"false"`
+	PasswordRequireLower      bool          `env:"PASSWORD_REQUIRE_LOWER" envDefault:"true"`
+	PasswordRequireUpper      bool          `env:"PASSWORD_REQUIRE_UPPER" envDefault:"true"`
+	PasswordRequireDigit      bool          `env:"PASSWORD_REQUIRE_DIGIT" envDefault:"true"`
+	PasswordRequireSymbol     bool          `env:"PASSWORD_REQUIRE_SYMBOL" envDefault:"false"`
+	TrustedProxies            []string      `env:"TRUSTED_PROXIES" envSeparator:","`
+	SsoGoogle                 SsoProvider   `envPrefix:"SSO_GOOGLE_"`
+	SsoGithub                 SsoProvider   `envPrefix:"SSO_GITHUB_"`
+	SsoOidc                   SsoProvider   `envPrefix:"SSO_OIDC_"`
 }
 
 func (cfg Configuration) ServerAddress() string {
@@ -1 +1,66 @@
 package constants
+
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+	WorkspaceRoleViewer = "viewer"
+)
+
+// WorkspaceImageSizes are the square thumbnail sizes, in pixels, generated for workspace images.
+var WorkspaceImageSizes = []int{64, 256}
+
+// WorkspaceRoleRank orders workspace roles from the least to the most privileged.
+var WorkspaceRoleRank = map[string]int{
+	WorkspaceRoleViewer: 1,
+	WorkspaceRoleMember: 2,
+	WorkspaceRoleAdmin:  3,
+	WorkspaceRoleOwner:  4,
+}
+
+// CanManageWorkspaceRole reports whether a member with actorRole may grant, change or remove targetRole.
+// Owners manage everyone, admins manage members and viewers.
+func CanManageWorkspaceRole(actorRole, targetRole string) bool {
+	if actorRole == WorkspaceRoleOwner {
+		return true
+	}
+	return actorRole == WorkspaceRoleAdmin && WorkspaceRoleRank[targetRole] < WorkspaceRoleRank[WorkspaceRoleAdmin]
+}
+
+const (
+	IssuePriorityLowest  = "lowest"
+	IssuePriorityLow     = "low"
+	IssuePriorityMedium  = "medium"
+	IssuePriorityHigh    = "high"
+	IssuePriorityHighest = "highest"
+)
+
+const (
+	IssueStatusTodo       = "To Do"
+	IssueStatusInProgress = "In Progress"
+	IssueStatusDone       = "Done"
+)
+
+const (
+	WorkflowCategoryTodo       = "todo"
+	WorkflowCategoryInProgress = "in_progress"
+	WorkflowCategoryDone       = "done"
+)
+
+const (
+	SprintStatePlanned = "planned"
+	SprintStateActive  = "active"
+	SprintStateClosed  = "closed"
+)
+
+const (
+	SearchTypeWorkspace = "workspace"
+	SearchTypeProject   = "project"
+	SearchTypeIssue     = "issue"
+	SearchTypeComment   = "comment"
+)
+
+const (
+	UserTokenEmailVerification = "email_verification"
+	UserTokenPasswordReset     = "password_reset"
+)
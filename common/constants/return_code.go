@@ -1 +1,12 @@
 package constants
+
+// Return codes tell apart business errors that share the same HTTP status code.
+const (
+	ReturnCodeWorkflowInvalid           = 1001
+	ReturnCodeWorkflowStatusInUse       = 1002
+	ReturnCodeIssueStatusNotInWorkflow  = 1003
+	ReturnCodeIssueTransitionNotAllowed = 1004
+	ReturnCodeSprintActiveExists        = 1005
+	ReturnCodeSprintStateInvalid        = 1006
+	ReturnCodeJQLInvalid                = 1007
+)
@@ -1 +1,25 @@
 package validator
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+	"jira-clone-api/utilities/password"
+)
+
+var projectKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+var customValidateFunctions = []ValidateFunction{
+	{Tag: "project_key", Function: validateProjectKey},
+	{Tag: "password", Function: validatePassword},
+}
+
+// validateProjectKey accepts issue key prefixes such as "WEB" or "API2".
+func validateProjectKey(fl validator.FieldLevel) bool {
+	return projectKeyRegex.MatchString(fl.Field().String())
+}
+
+// validatePassword applies the password policy of the configuration, see password.Service.CheckPolicy.
+func validatePassword(fl validator.FieldLevel) bool {
+	return password.New().CheckPolicy(fl.Field().String()) == nil
+}
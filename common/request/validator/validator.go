@@ -19,7 +19,7 @@ type ValidateFunction struct {
 
 func InitValidateEngine() *validator.Validate {
 	validateEngine = validator.New()
-	RegisterValidate()
+	RegisterValidate(customValidateFunctions...)
 
 	return validateEngine
 }
@@ -9,6 +9,33 @@ var (
 	ErrTokenWrong       = "Token is wrong"
 	ErrTokenRevoked     = "Token is revoked"
 
+	ErrLoginFailed          = "Username or password is incorrect"
+	ErrPasswordWrong        = "Password is incorrect"
+	ErrLoginLocked          = "Too many failed logins, try again later"
+	ErrEmailNotVerified     = "Email is not verified"
+	ErrEmailAlreadyVerified = "Email is already verified"
+	ErrMfaRequired          = "Two-factor authentication is required"
+	ErrMfaCodeWrong         = "Authentication code is incorrect"
+	ErrMfaAlreadyEnabled    = "Two-factor authentication is already enabled"
+	ErrMfaNotEnabled        = "Two-factor authentication is not enabled"
+	ErrMfaNotEnrolled       = "Two-factor authentication has not been enrolled"
+
+	ErrSsoProviderNotFound   = "SSO provider is not configured"
+	ErrSsoStateWrong         = "SSO state is wrong or expired"
+	ErrSsoFailed             = "SSO login failed"
+	ErrSsoEmailNotVerified   = "SSO account has no verified email"
+	ErrSsoAccountNotLinkable = "An account with this email exists but cannot be linked automatically"
+
+	ErrPermissionDenied  = "Permission denied"
+	ErrLastOwnerRequired = "Workspace must keep at least one owner"
+
+	ErrWorkflowStatusInUse       = "Removed statuses are still used by issues"
+	ErrIssueStatusNotInWorkflow  = "Status is not part of the workflow"
+	ErrIssueTransitionNotAllowed = "Status transition is not allowed by the workflow"
+
+	ErrSprintActiveExists = "Another sprint is already active"
+	ErrSprintStateInvalid = "Sprint state does not allow this action"
+
 	ErrUrlNotFound            = "URL not found"
 	ErrQueryMethodNotAllowed  = "Query method not allowed"
 	ErrQueryByFieldNotAllowed = "Query by field not allowed"
@@ -16,4 +43,6 @@ var (
 	ErrFieldWrongType         = "Field wrong type"
 
 	ErrTransactionExpired = "Transaction is expired"
+	ErrTransactionRevoked = "Transaction is revoked"
+	ErrTransactionUsed    = "Transaction is already used"
 )
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"jira-clone-api/common/configure"
+	"jira-clone-api/common/constants"
 	"jira-clone-api/common/logging"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -23,6 +24,7 @@ var (
 func InitDatabase() {
 	jiraDBClient = initClientConnection(cfg.MongoDBJiraUri, cfg.ElasticAPMEnable)
 	autoIndexing()
+	autoMigration()
 }
 
 func initClientConnection(mongoURI string, enableAPM bool) *mongo.Client {
@@ -42,9 +44,28 @@ func initClientConnection(mongoURI string, enableAPM bool) *mongo.Client {
 	if err = client.Ping(ctxPing, nil); err != nil {
 		logger.Fatal().Err(err).Str("function", "initClientConnection").Str("functionInline", "client.Ping").Msg("database")
 	}
+	requireReplicaSet(client)
 	return client
 }
 
+// requireReplicaSet stops the startup on a standalone server, which would only fail later on the
+// first write that needs a transaction. See the README for a local replica set.
+func requireReplicaSet(client *mongo.Client) {
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		logger.Fatal().Err(err).Str("function", "requireReplicaSet").Str("functionInline", "client.Database().RunCommand").Msg("database")
+	}
+	// mongos answers with msg "isdbgrid" and supports transactions as well.
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		logger.Fatal().Msg("MongoDB must run as a replica set, transactions are not supported on a standalone server")
+	}
+}
+
 func DisconnectDatabase() {
 	_ = jiraDBClient.Disconnect(context.Background())
 }
@@ -56,6 +77,19 @@ func autoIndexing() {
 	jiraUserIndex()
 	jiraTokenIndex()
 	jiraWorkspaceIndex()
+	jiraWorkspaceMemberIndex()
+	jiraWorkspaceInvitationIndex()
+	jiraProjectIndex()
+	jiraIssueIndex()
+	jiraWorkflowIndex()
+	jiraSprintIndex()
+	jiraCommentIndex()
+	jiraAttachmentIndex()
+	jiraSavedFilterIndex()
+	jiraUserTokenIndex()
+	jiraLoginAttemptIndex()
+	jiraSsoStateIndex()
+	jiraUserIdentityIndex()
 }
 
 func jiraUserIndex() {
@@ -70,6 +104,10 @@ func jiraUserIndex() {
 			Keys:    bson.D{{Key: "username", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+		},
 	}); err != nil {
 		logger.Fatal().Err(err).Msg("jiraUserIndex")
 	}
@@ -87,6 +125,12 @@ func jiraTokenIndex() {
 			Keys:    bson.D{{Key: "expired_at", Value: 1}},
 			Options: options.Index().SetExpireAfterSeconds(0),
 		},
+		{
+			Keys: bson.D{{Key: "family_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "revoked_at", Value: 1}},
+		},
 	}); err != nil {
 		logger.Fatal().Err(err).Msg("jiraTokenIndex")
 	}
@@ -104,7 +148,330 @@ func jiraWorkspaceIndex() {
 			Keys:    bson.D{{Key: "name", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			// Text indexes use the "none" language so Vietnamese words are neither stemmed nor dropped as
+			// stop words; the index still folds diacritics on its own.
+			Keys:    bson.D{{Key: "name", Value: "text"}},
+			Options: options.Index().SetDefaultLanguage("none"),
+		},
+		{
+			Keys: bson.D{{Key: "name_search", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "name_grams", Value: 1}},
+		},
 	}); err != nil {
 		logger.Fatal().Err(err).Msg("jiraWorkspaceIndex")
 	}
 }
+
+func jiraWorkspaceMemberIndex() {
+	collIndex := utils.GetWorkspaceMemberCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraWorkspaceMemberIndex")
+	}
+}
+
+func jiraWorkspaceInvitationIndex() {
+	collIndex := utils.GetWorkspaceInvitationCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expired_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}},
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraWorkspaceInvitationIndex")
+	}
+}
+
+func jiraProjectIndex() {
+	collIndex := utils.GetProjectCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "key", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetDefaultLanguage("none").SetWeights(bson.M{"name": 10, "key": 10, "description": 1}),
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "name_search", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "name_grams", Value: 1}},
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraProjectIndex")
+	}
+}
+
+func jiraIssueIndex() {
+	collIndex := utils.GetIssueCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "project_id", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "status", Value: 1}, {Key: "rank", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "assignee_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "sprint_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "key", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetDefaultLanguage("none").SetWeights(bson.M{"title": 10, "key": 10, "description": 1}),
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "title_search", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "title_grams", Value: 1}},
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraIssueIndex")
+	}
+}
+
+func jiraWorkflowIndex() {
+	collIndex := utils.GetWorkflowCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraWorkflowIndex")
+	}
+}
+
+func jiraSprintIndex() {
+	collIndex := utils.GetSprintCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "state", Value: 1}},
+		},
+		{
+			// At most one active sprint per project, or per workspace for sprints without a project.
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "project_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"state": constants.SprintStateActive}),
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "name_search", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "name_grams", Value: 1}},
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraSprintIndex")
+	}
+}
+
+func jiraCommentIndex() {
+	collIndex := utils.GetCommentCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "issue_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "project_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "body", Value: "text"}},
+			Options: options.Index().SetDefaultLanguage("none"),
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraCommentIndex")
+	}
+}
+
+func jiraAttachmentIndex() {
+	collIndex := utils.GetAttachmentCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "issue_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expired_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "project_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}},
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraAttachmentIndex")
+	}
+}
+
+func jiraSavedFilterIndex() {
+	collIndex := utils.GetSavedFilterCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraSavedFilterIndex")
+	}
+}
+
+func jiraUserTokenIndex() {
+	collIndex := utils.GetUserTokenCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expired_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "purpose", Value: 1}},
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraUserTokenIndex")
+	}
+}
+
+func jiraLoginAttemptIndex() {
+	collIndex := utils.GetLoginAttemptCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expired_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraLoginAttemptIndex")
+	}
+}
+
+func jiraSsoStateIndex() {
+	collIndex := utils.GetSsoStateCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "state", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expired_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraSsoStateIndex")
+	}
+}
+
+func jiraUserIdentityIndex() {
+	collIndex := utils.GetUserIdentityCollection().Indexes()
+	ctxDrop, cancelDrop := utils.GetContextTimeout(context.Background())
+	defer cancelDrop()
+	_, _ = collIndex.DropAll(ctxDrop)
+	ctx, cancel := utils.GetContextTimeout(context.Background())
+	defer cancel()
+	if _, err := collIndex.CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "subject", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("jiraUserIdentityIndex")
+	}
+}
@@ -0,0 +1,213 @@
+package mongo
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/constants"
+	mongoModels "jira-clone-api/database/mongo/models"
+	"jira-clone-api/utilities/lexorank"
+)
+
+type migration struct {
+	up   func(ctx context.Context) error
+	name string
+}
+
+// migrations run once each, in order, and are recorded in the migrations collection.
+// Append new entries at the end and never rename an applied one.
+var migrations = []migration{
+	{name: "0001_workspace_owner_members", up: migrateWorkspaceOwnerMembers},
+	{name: "0002_workspace_workflows", up: migrateWorkspaceWorkflows},
+	{name: "0003_issue_ranks", up: migrateIssueRanks},
+	{name: "0004_search_fields", up: migrateSearchFields},
+}
+
+func autoMigration() {
+	if !cfg.MongoAutoMigration {
+		return
+	}
+	coll := utils.GetMigrationCollection()
+	for _, m := range migrations {
+		ctxFind, cancelFind := utils.GetContextTimeout(context.Background())
+		count, err := coll.CountDocuments(ctxFind, bson.M{"_id": m.name})
+		cancelFind()
+		if err != nil {
+			logger.Fatal().Err(err).Str("migration", m.name).Msg("autoMigration")
+		}
+		if count > 0 {
+			continue
+		}
+		ctx, cancel := utils.GetContextTimeout(context.Background())
+		if err = m.up(ctx); err != nil {
+			cancel()
+			logger.Fatal().Err(err).Str("migration", m.name).Msg("autoMigration")
+		}
+		_, err = coll.InsertOne(ctx, mongoModels.Migration{Id: m.name, AppliedAt: time.Now()})
+		cancel()
+		if err != nil {
+			logger.Fatal().Err(err).Str("migration", m.name).Msg("autoMigration")
+		}
+		logger.Info().Str("migration", m.name).Msg("migration applied")
+	}
+}
+
+// migrateWorkspaceOwnerMembers gives every workspace created before memberships existed an owner member.
+func migrateWorkspaceOwnerMembers(ctx context.Context) error {
+	cursor, err := utils.GetWorkspaceCollection().Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1, "user_id": 1, "created_at": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	models := make([]mongo.WriteModel, 0)
+	for cursor.Next(ctx) {
+		var workspace mongoModels.Workspace
+		if err = cursor.Decode(&workspace); err != nil {
+			return err
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"workspace_id": workspace.Id, "user_id": workspace.UserId}).
+			SetUpdate(bson.M{"$setOnInsert": mongoModels.WorkspaceMember{
+				CreatedAt:   workspace.CreatedAt,
+				UpdatedAt:   workspace.CreatedAt,
+				Role:        constants.WorkspaceRoleOwner,
+				WorkspaceId: workspace.Id,
+				UserId:      workspace.UserId,
+			}}).
+			SetUpsert(true))
+	}
+	if err = cursor.Err(); err != nil || len(models) == 0 {
+		return err
+	}
+	_, err = utils.GetWorkspaceMemberCollection().BulkWrite(ctx, models)
+	return err
+}
+
+// migrateWorkspaceWorkflows gives every workspace created before workflows existed the default workflow.
+func migrateWorkspaceWorkflows(ctx context.Context) error {
+	cursor, err := utils.GetWorkspaceCollection().Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1, "created_at": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	models := make([]mongo.WriteModel, 0)
+	for cursor.Next(ctx) {
+		var workspace mongoModels.Workspace
+		if err = cursor.Decode(&workspace); err != nil {
+			return err
+		}
+		workflow := mongoModels.NewDefaultWorkflow(workspace.Id)
+		workflow.CreatedAt = workspace.CreatedAt
+		workflow.UpdatedAt = workspace.CreatedAt
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"workspace_id": workspace.Id}).
+			SetUpdate(bson.M{"$setOnInsert": workflow}).
+			SetUpsert(true))
+	}
+	if err = cursor.Err(); err != nil || len(models) == 0 {
+		return err
+	}
+	_, err = utils.GetWorkflowCollection().BulkWrite(ctx, models)
+	return err
+}
+
+// migrateIssueRanks ranks the issues created before the board existed, keeping each column in creation order.
+func migrateIssueRanks(ctx context.Context) error {
+	optFind := options.Find().
+		SetProjection(bson.M{"_id": 1, "workspace_id": 1, "status": 1}).
+		SetSort(bson.D{{Key: "workspace_id", Value: 1}, {Key: "status", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := utils.GetIssueCollection().Find(ctx, bson.M{"rank": bson.M{"$exists": false}}, optFind)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	models := make([]mongo.WriteModel, 0)
+	column := make([]primitive.ObjectID, 0)
+	var current mongoModels.Issue
+	flush := func() {
+		ranks := lexorank.New().Spread(len(column))
+		for i, id := range column {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": id}).
+				SetUpdate(bson.M{"$set": bson.M{"rank": ranks[i]}}))
+		}
+		column = column[:0]
+	}
+	for cursor.Next(ctx) {
+		var issue mongoModels.Issue
+		if err = cursor.Decode(&issue); err != nil {
+			return err
+		}
+		if issue.WorkspaceId != current.WorkspaceId || issue.Status != current.Status {
+			flush()
+			current = issue
+		}
+		column = append(column, issue.Id)
+	}
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+	flush()
+	if len(models) == 0 {
+		return nil
+	}
+	_, err = utils.GetIssueCollection().BulkWrite(ctx, models)
+	return err
+}
+
+// migrateSearchFields fills the normalized search copies of documents written before they existed.
+func migrateSearchFields(ctx context.Context) error {
+	backfills := []struct {
+		collection *mongo.Collection
+		fields     []string
+	}{
+		{collection: utils.GetWorkspaceCollection(), fields: []string{"name"}},
+		{collection: utils.GetProjectCollection(), fields: []string{"name"}},
+		{collection: utils.GetSprintCollection(), fields: []string{"name"}},
+		{collection: utils.GetIssueCollection(), fields: []string{"title", "description"}},
+	}
+	for _, backfill := range backfills {
+		projection := bson.M{"_id": 1}
+		for _, field := range backfill.fields {
+			projection[field] = 1
+		}
+		filter := bson.M{mongoModels.SearchFields[backfill.fields[0]].Search: bson.M{"$exists": false}}
+		cursor, err := backfill.collection.Find(ctx, filter, options.Find().SetProjection(projection))
+		if err != nil {
+			return err
+		}
+		models := make([]mongo.WriteModel, 0)
+		for cursor.Next(ctx) {
+			var document bson.M
+			if err = cursor.Decode(&document); err != nil {
+				_ = cursor.Close(ctx)
+				return err
+			}
+			set := bson.M{}
+			for _, field := range backfill.fields {
+				value, _ := document[field].(string)
+				maps.Copy(set, mongoModels.SearchValues(field, value))
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": document["_id"]}).
+				SetUpdate(bson.M{"$set": set}))
+		}
+		err = cursor.Err()
+		_ = cursor.Close(ctx)
+		if err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			continue
+		}
+		if _, err = backfill.collection.BulkWrite(ctx, models); err != nil {
+			return err
+		}
+	}
+	return nil
+}
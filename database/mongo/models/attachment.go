@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Attachment is pending until the client confirms its upload. ExpiredAt is set while it is
+// pending and again once its issue is gone, so the cleanup job removes the object and document.
+type Attachment struct {
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	ExpiredAt   *time.Time         `bson:"expired_at,omitempty"`
+	ConfirmedAt *time.Time         `bson:"confirmed_at,omitempty"`
+	Filename    string             `bson:"filename"`
+	ObjectKey   string             `bson:"object_key"`
+	ContentType string             `bson:"content_type"`
+	Size        int64              `bson:"size"`
+	WorkspaceId primitive.ObjectID `bson:"workspace_id"`
+	ProjectId   primitive.ObjectID `bson:"project_id"`
+	IssueId     primitive.ObjectID `bson:"issue_id"`
+	UploadedBy  primitive.ObjectID `bson:"uploaded_by"`
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *Attachment) CollectionName() string {
+	return "attachments"
+}
+
+func (m *Attachment) IsConfirmed() bool {
+	return m.ConfirmedAt != nil
+}
+
+// UploadKey is where the client uploads the file. Confirming copies it to ObjectKey, so the upload
+// URL can no longer replace the confirmed object.
+func (m *Attachment) UploadKey() string {
+	return m.ObjectKey + ".upload"
+}
+
+// ObjectKeys are all keys the attachment may occupy in storage, including a leftover upload.
+func (m *Attachment) ObjectKeys() []string {
+	return []string{m.ObjectKey, m.UploadKey()}
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Comment struct {
+	CreatedAt   time.Time            `bson:"created_at"`
+	UpdatedAt   time.Time            `bson:"updated_at"`
+	EditedAt    *time.Time           `bson:"edited_at,omitempty"`
+	Body        string               `bson:"body"`
+	Mentions    []primitive.ObjectID `bson:"mentions"`
+	ReplyCount  int64                `bson:"reply_count"`
+	WorkspaceId primitive.ObjectID   `bson:"workspace_id"`
+	ProjectId   primitive.ObjectID   `bson:"project_id"`
+	IssueId     primitive.ObjectID   `bson:"issue_id"`
+	ParentId    primitive.ObjectID   `bson:"parent_id,omitempty"`
+	AuthorId    primitive.ObjectID   `bson:"author_id"`
+	Id          primitive.ObjectID   `bson:"_id,omitempty"`
+}
+
+func (m *Comment) CollectionName() string {
+	return "comments"
+}
+
+func (m *Comment) IsReply() bool {
+	return !m.ParentId.IsZero()
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Counter holds the last sequence number handed out for the document with the same id, e.g. a project.
+type Counter struct {
+	Seq         int64              `bson:"seq"`
+	WorkspaceId primitive.ObjectID `bson:"workspace_id"`
+	Id          primitive.ObjectID `bson:"_id"`
+}
+
+func (m *Counter) CollectionName() string {
+	return "counters"
+}
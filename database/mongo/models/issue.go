@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/utilities/textsearch"
+)
+
+type Issue struct {
+	CreatedAt         time.Time          `bson:"created_at"`
+	UpdatedAt         time.Time          `bson:"updated_at"`
+	DueDate           *time.Time         `bson:"due_date,omitempty"`
+	Key               string             `bson:"key"`
+	Title             string             `bson:"title"`
+	Description       string             `bson:"description"`
+	TitleSearch       string             `bson:"title_search"`
+	TitleGrams        []string           `bson:"title_grams"`
+	DescriptionSearch string             `bson:"description_search"`
+	Status            string             `bson:"status"`
+	Priority          string             `bson:"priority"`
+	Rank              string             `bson:"rank"`
+	Labels            []string           `bson:"labels"`
+	Number            int64              `bson:"number"`
+	WorkspaceId       primitive.ObjectID `bson:"workspace_id"`
+	ProjectId         primitive.ObjectID `bson:"project_id"`
+	AssigneeId        primitive.ObjectID `bson:"assignee_id,omitempty"`
+	SprintId          primitive.ObjectID `bson:"sprint_id,omitempty"`
+	ReporterId        primitive.ObjectID `bson:"reporter_id"`
+	Id                primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *Issue) CollectionName() string {
+	return "issues"
+}
+
+// SetSearchFields refreshes the normalized copies of the searchable fields before a write.
+func (m *Issue) SetSearchFields() {
+	m.TitleSearch = textsearch.New().Normalize(m.Title)
+	m.TitleGrams = textsearch.New().Grams(m.TitleSearch)
+	m.DescriptionSearch = textsearch.New().Normalize(m.Description)
+}
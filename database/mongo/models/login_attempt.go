@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LoginAttempt counts the recent failed logins of one key, a username or a client IP. The document
+// expires ExpiredAt after the last failure, which resets the count.
+type LoginAttempt struct {
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	ExpiredAt   time.Time          `bson:"expired_at"`
+	LockedUntil *time.Time         `bson:"locked_until,omitempty"`
+	Key         string             `bson:"key"`
+	Failures    int64              `bson:"failures"`
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *LoginAttempt) CollectionName() string {
+	return "login_attempts"
+}
+
+func LoginAttemptUsernameKey(username string) string {
+	return "username:" + username
+}
+
+func LoginAttemptIpKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
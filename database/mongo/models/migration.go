@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+type Migration struct {
+	AppliedAt time.Time `bson:"applied_at"`
+	Id        string    `bson:"_id"`
+}
+
+func (m *Migration) CollectionName() string {
+	return "migrations"
+}
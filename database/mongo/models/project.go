@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/utilities/textsearch"
+)
+
+type Project struct {
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	Name        string             `bson:"name"`
+	NameSearch  string             `bson:"name_search"`
+	NameGrams   []string           `bson:"name_grams"`
+	Key         string             `bson:"key"`
+	Description string             `bson:"description"`
+	Icon        string             `bson:"icon"`
+	WorkspaceId primitive.ObjectID `bson:"workspace_id"`
+	LeadId      primitive.ObjectID `bson:"lead_id,omitempty"`
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *Project) CollectionName() string {
+	return "projects"
+}
+
+// SetSearchFields refreshes the normalized copies of the searchable fields before a write.
+func (m *Project) SetSearchFields() {
+	m.NameSearch = textsearch.New().Normalize(m.Name)
+	m.NameGrams = textsearch.New().Grams(m.NameSearch)
+}
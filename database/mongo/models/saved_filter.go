@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SavedFilter is a named JQL query that belongs to one user in one workspace.
+type SavedFilter struct {
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	Name        string             `bson:"name"`
+	JQL         string             `bson:"jql"`
+	WorkspaceId primitive.ObjectID `bson:"workspace_id"`
+	UserId      primitive.ObjectID `bson:"user_id"`
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *SavedFilter) CollectionName() string {
+	return "saved_filters"
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"jira-clone-api/utilities/textsearch"
+)
+
+// SearchField names the shadow copies kept next to a searchable text field. Search holds the value
+// normalized by textsearch; Grams holds its trigrams and is empty for fields too long to index them.
+type SearchField struct {
+	Search string
+	Grams  string
+}
+
+// SearchFields maps every searchable text field to its shadow copies.
+var SearchFields = map[string]SearchField{
+	"name":        {Search: "name_search", Grams: "name_grams"},
+	"title":       {Search: "title_search", Grams: "title_grams"},
+	"description": {Search: "description_search"},
+}
+
+// SearchValues returns the shadow copies of field for value, ready to be $set next to it.
+func SearchValues(field, value string) bson.M {
+	searchField, ok := SearchFields[field]
+	if !ok {
+		return bson.M{}
+	}
+	normalized := textsearch.New().Normalize(value)
+	values := bson.M{searchField.Search: normalized}
+	if searchField.Grams != "" {
+		values[searchField.Grams] = textsearch.New().Grams(normalized)
+	}
+	return values
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/utilities/textsearch"
+)
+
+type Sprint struct {
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	StartDate   *time.Time         `bson:"start_date,omitempty"`
+	EndDate     *time.Time         `bson:"end_date,omitempty"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty"`
+	Name        string             `bson:"name"`
+	NameSearch  string             `bson:"name_search"`
+	NameGrams   []string           `bson:"name_grams"`
+	Goal        string             `bson:"goal"`
+	State       string             `bson:"state"`
+	WorkspaceId primitive.ObjectID `bson:"workspace_id"`
+	ProjectId   primitive.ObjectID `bson:"project_id,omitempty"`
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *Sprint) CollectionName() string {
+	return "sprints"
+}
+
+// SetSearchFields refreshes the normalized copies of the searchable fields before a write.
+func (m *Sprint) SetSearchFields() {
+	m.NameSearch = textsearch.New().Normalize(m.Name)
+	m.NameGrams = textsearch.New().Grams(m.NameSearch)
+}
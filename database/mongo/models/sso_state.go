@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SsoState remembers a single sign-on login between sending the browser to the provider and its
+// return. It is deleted when the callback consumes it, so every state is used at most once.
+type SsoState struct {
+	CreatedAt    time.Time          `bson:"created_at"`
+	ExpiredAt    time.Time          `bson:"expired_at"`
+	Provider     string             `bson:"provider"`
+	State        string             `bson:"state"`
+	Nonce        string             `bson:"nonce"`
+	CodeVerifier string             `bson:"code_verifier"`
+	Id           primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *SsoState) CollectionName() string {
+	return "sso_states"
+}
@@ -7,13 +7,22 @@ import (
 )
 
 type Token struct {
-	UpdatedAt time.Time          `bson:"updated_at"`
-	CreatedAt time.Time          `bson:"created_at"`
-	ExpiredAt time.Time          `bson:"expired_at"`
-	UserId    primitive.ObjectID `bson:"user_id"`
-	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	UpdatedAt  time.Time          `bson:"updated_at"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	ExpiredAt  time.Time          `bson:"expired_at"`
+	LastUsedAt time.Time          `bson:"last_used_at"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty"`
+	UserAgent  string             `bson:"user_agent"`
+	IpAddress  string             `bson:"ip_address"`
+	UserId     primitive.ObjectID `bson:"user_id"`
+	FamilyId   primitive.ObjectID `bson:"family_id"`
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
 }
 
 func (m *Token) CollectionName() string {
 	return "tokens"
 }
+
+func (m *Token) IsRevoked() bool {
+	return m.RevokedAt != nil
+}
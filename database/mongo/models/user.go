@@ -7,14 +7,32 @@ import (
 )
 
 type User struct {
-	CreatedAt time.Time          `bson:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at"`
-	Username  string             `bson:"username"`
-	Password  string             `bson:"password"`
-	Email     string             `bson:"email"`
-	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at"`
+	EmailVerifiedAt  *time.Time         `bson:"email_verified_at,omitempty"`
+	MfaEnabledAt     *time.Time         `bson:"mfa_enabled_at,omitempty"`
+	Username         string             `bson:"username"`
+	Password         string             `bson:"password"`
+	Email            string             `bson:"email"`
+	MfaSecret        string             `bson:"mfa_secret,omitempty"`
+	MfaRecoveryCodes []string           `bson:"mfa_recovery_codes,omitempty"`
+	MfaLastStep      int64              `bson:"mfa_last_step,omitempty"`
+	Id               primitive.ObjectID `bson:"_id,omitempty"`
+	// IsAdmin grants the instance-wide administration endpoints. It is only ever set in the database.
+	IsAdmin bool `bson:"is_admin,omitempty"`
 }
 
 func (m *User) CollectionName() string {
 	return "users"
 }
+
+func (m *User) IsEmailVerified() bool {
+	return m.EmailVerifiedAt != nil
+}
+
+// IsMfaEnabled reports whether logins need a second factor. MfaSecret is set at enrolment but only
+// trusted once a first code confirmed it; MfaRecoveryCodes holds the SHA-256 of the unused recovery
+// codes and MfaLastStep the last TOTP period a code was accepted for, so no code is accepted twice.
+func (m *User) IsMfaEnabled() bool {
+	return m.MfaEnabledAt != nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserIdentity links a user to an account at a single sign-on provider. Subject is the id the
+// provider gives the account; Email is only kept to show which account was linked.
+type UserIdentity struct {
+	CreatedAt   time.Time          `bson:"created_at"`
+	LastLoginAt time.Time          `bson:"last_login_at"`
+	Provider    string             `bson:"provider"`
+	Subject     string             `bson:"subject"`
+	Email       string             `bson:"email"`
+	UserId      primitive.ObjectID `bson:"user_id"`
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *UserIdentity) CollectionName() string {
+	return "user_identities"
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserToken is a single-use secret mailed to a user, such as an email verification or password
+// reset link. Only the SHA-256 hash of the secret is stored, so a database leak cannot replay it.
+type UserToken struct {
+	CreatedAt time.Time          `bson:"created_at"`
+	ExpiredAt time.Time          `bson:"expired_at"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty"`
+	Purpose   string             `bson:"purpose"`
+	Hash      string             `bson:"hash"`
+	UserId    primitive.ObjectID `bson:"user_id"`
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *UserToken) CollectionName() string {
+	return "user_tokens"
+}
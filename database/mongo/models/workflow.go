@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/constants"
+	respErr "jira-clone-api/common/response/error"
+)
+
+// Reasons CanTransition refuses a status change; queries.WorkflowError turns them into responses.
+var (
+	ErrWorkflowStatusUnknown     = errors.New(respErr.ErrIssueStatusNotInWorkflow)
+	ErrWorkflowTransitionRefused = errors.New(respErr.ErrIssueTransitionNotAllowed)
+)
+
+type WorkflowStatus struct {
+	Name     string `bson:"name"`
+	Category string `bson:"category"`
+}
+
+type WorkflowTransition struct {
+	From string `bson:"from"`
+	To   string `bson:"to"`
+}
+
+type Workflow struct {
+	CreatedAt     time.Time            `bson:"created_at"`
+	UpdatedAt     time.Time            `bson:"updated_at"`
+	InitialStatus string               `bson:"initial_status"`
+	Statuses      []WorkflowStatus     `bson:"statuses"`
+	Transitions   []WorkflowTransition `bson:"transitions"`
+	WorkspaceId   primitive.ObjectID   `bson:"workspace_id"`
+	Id            primitive.ObjectID   `bson:"_id,omitempty"`
+}
+
+func (m *Workflow) CollectionName() string {
+	return "workflows"
+}
+
+func (m *Workflow) HasStatus(name string) bool {
+	for _, status := range m.Statuses {
+		if status.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusesInCategory returns the names of the statuses grouped under category.
+func (m *Workflow) StatusesInCategory(category string) []string {
+	names := make([]string, 0)
+	for _, status := range m.Statuses {
+		if status.Category == category {
+			names = append(names, status.Name)
+		}
+	}
+	return names
+}
+
+// CanTransition checks that an issue may move from one status to another. Staying in the same
+// status is always allowed as long as the workflow still declares it.
+func (m *Workflow) CanTransition(from, to string) error {
+	if !m.HasStatus(to) {
+		return ErrWorkflowStatusUnknown
+	}
+	if from != to && !m.HasTransition(from, to) {
+		return ErrWorkflowTransitionRefused
+	}
+	return nil
+}
+
+func (m *Workflow) HasTransition(from, to string) bool {
+	for _, transition := range m.Transitions {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDefaultWorkflow is the workflow every workspace starts with: To Do, In Progress and Done,
+// with every transition between them allowed.
+func NewDefaultWorkflow(workspaceId primitive.ObjectID) Workflow {
+	statuses := []WorkflowStatus{
+		{Name: constants.IssueStatusTodo, Category: constants.WorkflowCategoryTodo},
+		{Name: constants.IssueStatusInProgress, Category: constants.WorkflowCategoryInProgress},
+		{Name: constants.IssueStatusDone, Category: constants.WorkflowCategoryDone},
+	}
+	transitions := make([]WorkflowTransition, 0, len(statuses)*(len(statuses)-1))
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if from.Name != to.Name {
+				transitions = append(transitions, WorkflowTransition{From: from.Name, To: to.Name})
+			}
+		}
+	}
+	return Workflow{
+		InitialStatus: constants.IssueStatusTodo,
+		Statuses:      statuses,
+		Transitions:   transitions,
+		WorkspaceId:   workspaceId,
+	}
+}
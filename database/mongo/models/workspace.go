@@ -1,20 +1,61 @@
 package models
 
 import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/utilities/textsearch"
 )
 
 type Workspace struct {
-	CreatedAt time.Time          `bson:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at"`
-	Name      string             `bson:"name"`
-	ImageName string             `bson:"image_name"`
-	UserId    primitive.ObjectID `bson:"user_id"`
-	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at"`
+	DeletedAt  *time.Time         `bson:"deleted_at,omitempty"`
+	Name       string             `bson:"name"`
+	NameSearch string             `bson:"name_search"`
+	NameGrams  []string           `bson:"name_grams"`
+	ImageName  string             `bson:"image_name"`
+	ImageSizes []int              `bson:"image_sizes,omitempty"`
+	UserId     primitive.ObjectID `bson:"user_id"`
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
 }
 
 func (m *Workspace) CollectionName() string {
 	return "workspaces"
 }
+
+func (m *Workspace) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
+// ImageKey returns the object key of the size x size thumbnail, which is stored next to the
+// original. It falls back to the original when that thumbnail was never generated.
+func (m *Workspace) ImageKey(size int) string {
+	if m.ImageName == "" || !slices.Contains(m.ImageSizes, size) {
+		return m.ImageName
+	}
+	ext := path.Ext(m.ImageName)
+	return fmt.Sprintf("%s_%d%s", strings.TrimSuffix(m.ImageName, ext), size, ext)
+}
+
+// ImageKeys returns the keys of the original image and all of its thumbnails.
+func (m *Workspace) ImageKeys() []string {
+	if m.ImageName == "" {
+		return nil
+	}
+	keys := []string{m.ImageName}
+	for _, size := range m.ImageSizes {
+		keys = append(keys, m.ImageKey(size))
+	}
+	return keys
+}
+
+// SetSearchFields refreshes the normalized copies of the searchable fields before a write.
+func (m *Workspace) SetSearchFields() {
+	m.NameSearch = textsearch.New().Normalize(m.Name)
+	m.NameGrams = textsearch.New().Grams(m.NameSearch)
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WorkspaceInvitation is addressed to an email and accepted once, or is a shareable link
+// (empty Email) that anyone holding the code can accept until it expires or is revoked.
+type WorkspaceInvitation struct {
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	ExpiredAt   time.Time          `bson:"expired_at"`
+	AcceptedAt  *time.Time         `bson:"accepted_at,omitempty"`
+	RevokedAt   *time.Time         `bson:"revoked_at,omitempty"`
+	Email       string             `bson:"email"`
+	Role        string             `bson:"role"`
+	WorkspaceId primitive.ObjectID `bson:"workspace_id"`
+	CreatedBy   primitive.ObjectID `bson:"created_by"`
+	AcceptedBy  primitive.ObjectID `bson:"accepted_by,omitempty"`
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *WorkspaceInvitation) CollectionName() string {
+	return "workspace_invitations"
+}
+
+func (m *WorkspaceInvitation) IsShareableLink() bool {
+	return m.Email == ""
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WorkspaceMember struct {
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	Role        string             `bson:"role"`
+	WorkspaceId primitive.ObjectID `bson:"workspace_id"`
+	UserId      primitive.ObjectID `bson:"user_id"`
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+}
+
+func (m *WorkspaceMember) CollectionName() string {
+	return "workspace_members"
+}
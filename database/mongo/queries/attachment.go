@@ -0,0 +1,168 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type AttachmentQuery interface {
+	Create(attachment models.Attachment) (newAttachment *models.Attachment, err error)
+	GetByIdAndIssueId(id, issueId primitive.ObjectID, opts ...OptionsQuery) (attachment *models.Attachment, err error)
+	GetConfirmedByIssueId(issueId primitive.ObjectID, opts ...OptionsQuery) ([]models.Attachment, error)
+	GetExpiredBefore(expiredBefore time.Time, opts ...OptionsQuery) ([]models.Attachment, error)
+	ConfirmById(id primitive.ObjectID, contentType string, size int64) (attachment *models.Attachment, err error)
+	ExpireByIssueId(issueId primitive.ObjectID) error
+	ExpireByProjectId(projectId primitive.ObjectID) error
+	ExpireByWorkspaceId(workspaceId primitive.ObjectID) error
+	DeleteById(id primitive.ObjectID) error
+}
+
+type attachmentQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewAttachment(ctx context.Context) AttachmentQuery {
+	return &attachmentQuery{
+		collection: mongo.NewUtilityService().GetAttachmentCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *attachmentQuery) Create(data models.Attachment) (*models.Attachment, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("attachmentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *attachmentQuery) GetByIdAndIssueId(id, issueId primitive.ObjectID, opts ...OptionsQuery) (*models.Attachment, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.Attachment
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"_id": id, "issue_id": issueId}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Attachment not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetByIdAndIssueId").Str("functionInline", "q.collection.FindOne").Msg("attachmentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+func (q *attachmentQuery) GetConfirmedByIssueId(issueId primitive.ObjectID, opts ...OptionsQuery) ([]models.Attachment, error) {
+	return q.find(bson.M{"issue_id": issueId, "confirmed_at": bson.M{"$ne": nil}, "expired_at": nil}, "GetConfirmedByIssueId", opts...)
+}
+
+func (q *attachmentQuery) GetExpiredBefore(expiredBefore time.Time, opts ...OptionsQuery) ([]models.Attachment, error) {
+	return q.find(bson.M{"expired_at": bson.M{"$lte": expiredBefore}}, "GetExpiredBefore", opts...)
+}
+
+func (q *attachmentQuery) find(filter bson.M, function string, opts ...OptionsQuery) ([]models.Attachment, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var attachments []models.Attachment
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Limit:      opt.QueryPaginationLimit(),
+		Skip:       opt.QueryPaginationSkip(),
+		Sort:       opt.QuerySort(),
+	}
+	cursor, err := q.collection.Find(ctx, filter, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "q.collection.Find").Msg("attachmentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &attachments); err != nil {
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "cursor.All").Msg("attachmentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return attachments, nil
+}
+
+// ConfirmById records the uploaded object's real size and type. It only applies to a pending
+// attachment that has not expired yet.
+func (q *attachmentQuery) ConfirmById(id primitive.ObjectID, contentType string, size int64) (*models.Attachment, error) {
+	currentTime := time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var attachment models.Attachment
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	filter := bson.M{"_id": id, "confirmed_at": nil, "expired_at": bson.M{"$gt": currentTime}}
+	update := bson.M{
+		"$set":   bson.M{"confirmed_at": currentTime, "content_type": contentType, "size": size, "updated_at": currentTime},
+		"$unset": bson.M{"expired_at": ""},
+	}
+	if err := q.collection.FindOneAndUpdate(ctx, filter, update, optUpdate).Decode(&attachment); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Attachment is already confirmed or its upload expired"})
+		}
+		logger.Error().Err(err).Str("function", "ConfirmById").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("attachmentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &attachment, nil
+}
+
+func (q *attachmentQuery) ExpireByIssueId(issueId primitive.ObjectID) error {
+	return q.expire(bson.M{"issue_id": issueId}, "ExpireByIssueId")
+}
+
+func (q *attachmentQuery) ExpireByProjectId(projectId primitive.ObjectID) error {
+	return q.expire(bson.M{"project_id": projectId}, "ExpireByProjectId")
+}
+
+func (q *attachmentQuery) ExpireByWorkspaceId(workspaceId primitive.ObjectID) error {
+	return q.expire(bson.M{"workspace_id": workspaceId}, "ExpireByWorkspaceId")
+}
+
+// expire hands the matching attachments over to the cleanup job, which also removes their objects.
+func (q *attachmentQuery) expire(filter bson.M, function string) error {
+	currentTime := time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"expired_at": currentTime, "updated_at": currentTime}}); err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "q.collection.UpdateMany").Msg("attachmentQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *attachmentQuery) DeleteById(id primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteById").Str("functionInline", "q.collection.DeleteOne").Msg("attachmentQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
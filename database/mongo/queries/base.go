@@ -1,21 +1,25 @@
 package queries
 
 import (
+	"context"
+	"errors"
+	"maps"
 	"regexp"
-	"strings"
 
 	"jira-clone-api/common/configure"
 	"jira-clone-api/common/logging"
 	"jira-clone-api/common/request"
 	"jira-clone-api/common/response"
 	"jira-clone-api/database/mongo"
-	"jira-clone-api/utilities/tool"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/utilities/textsearch"
 
 	respErr "jira-clone-api/common/response/error"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
 )
 
 var (
@@ -24,6 +28,26 @@ var (
 	timeoutFunc = mongo.NewUtilityService().GetContextTimeout
 )
 
+// WithTransaction runs fn in a Mongo transaction (the server must be a replica set).
+// Queries created with the context passed to fn join the transaction.
+func WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := mongo.NewUtilityService().WithTransaction(ctx, fn)
+	if err == nil {
+		return nil
+	}
+	if e := new(response.Error); errors.As(err, &e) {
+		return err
+	}
+	logger.Error().Err(err).Str("function", "WithTransaction").Str("functionInline", "mongo.NewUtilityService().WithTransaction").Msg("queries")
+	return response.NewError(fiber.StatusInternalServerError)
+}
+
+// isTransientTransactionError reports errors that must reach WithTransaction untouched so the driver retries them.
+func isTransientTransactionError(err error) bool {
+	var labeledErr mongoDriver.LabeledError
+	return errors.As(err, &labeledErr) && labeledErr.HasErrorLabel("TransientTransactionError")
+}
+
 const (
 	QueryMethodEqual = iota
 	QueryMethodNotEqual
@@ -38,6 +62,9 @@ const (
 	QueryMethodNotIn
 )
 
+// maxFilterGrams bounds the trigrams a substring filter requires; the regex still checks the whole value.
+const maxFilterGrams = 8
+
 const (
 	SortTypeDesc = -1
 	SortTypeAsc  = 1
@@ -74,6 +101,7 @@ type OptionsQuery interface {
 	QuerySort() bson.D
 	ResetSort()
 	AddSortKey(map[string]int)
+	AddSortKeys(sorts bson.D)
 }
 
 type optionsQuery struct {
@@ -137,6 +165,16 @@ func (o *optionsQuery) AddSortKey(sorts map[string]int) {
 	}
 }
 
+// AddSortKeys appends sorts in order, unlike AddSortKey whose map loses the order of several keys.
+func (o *optionsQuery) AddSortKeys(sorts bson.D) {
+	for _, sort := range sorts {
+		if sort.Value != SortTypeAsc && sort.Value != SortTypeDesc {
+			sort.Value = SortTypeDesc
+		}
+		o.sort = append(o.sort, sort)
+	}
+}
+
 func (o *optionsQuery) ResetSort() {
 	o.sort = make(bson.D, 0)
 }
@@ -149,6 +187,8 @@ type Filter struct {
 	Value  interface{}
 	By     string
 	Method int
+	// Search matches the text methods against the normalized shadow copy of By, see models.SearchFields.
+	Search bool
 }
 
 type optionsFilter struct {
@@ -182,12 +222,8 @@ func (f *optionsFilter) BuildMongoFilterWithAndCondition() bson.M {
 	for _, v := range f.filters {
 		filter := make(bson.M)
 		switch v.Method {
-		case QueryMethodContains:
-			filter[v.By] = bson.M{queryMethodMap[v.Method]: primitive.Regex{Pattern: f.buildVietnameseRegex(regexp.QuoteMeta(v.Value.(string))), Options: "i"}}
-		case QueryMethodStartsWith:
-			filter[v.By] = bson.M{queryMethodMap[v.Method]: primitive.Regex{Pattern: "^" + f.buildVietnameseRegex(v.Value.(string)), Options: "i"}}
-		case QueryMethodEndsWith:
-			filter[v.By] = bson.M{queryMethodMap[v.Method]: primitive.Regex{Pattern: f.buildVietnameseRegex(v.Value.(string)) + "$", Options: "i"}}
+		case QueryMethodContains, QueryMethodStartsWith, QueryMethodEndsWith:
+			filter = buildTextFilter(v.By, v.Method, v.Value.(string), v.Search)
 		default:
 			id, ok := v.Value.(primitive.ObjectID)
 			if ok {
@@ -209,12 +245,8 @@ func (f *optionsFilter) BuildMongoFilterWithOrCondition() bson.M {
 	for _, v := range f.filters {
 		filter := make(bson.M)
 		switch v.Method {
-		case QueryMethodContains:
-			filter[v.By] = bson.M{queryMethodMap[v.Method]: primitive.Regex{Pattern: f.buildVietnameseRegex(regexp.QuoteMeta(v.Value.(string))), Options: "i"}}
-		case QueryMethodStartsWith:
-			filter[v.By] = bson.M{queryMethodMap[v.Method]: primitive.Regex{Pattern: "^" + f.buildVietnameseRegex(v.Value.(string)), Options: "i"}}
-		case QueryMethodEndsWith:
-			filter[v.By] = bson.M{queryMethodMap[v.Method]: primitive.Regex{Pattern: f.buildVietnameseRegex(v.Value.(string)) + "$", Options: "i"}}
+		case QueryMethodContains, QueryMethodStartsWith, QueryMethodEndsWith:
+			filter = buildTextFilter(v.By, v.Method, v.Value.(string), v.Search)
 		default:
 			id, ok := v.Value.(primitive.ObjectID)
 			if ok {
@@ -232,16 +264,42 @@ func (f *optionsFilter) ResetFilter() {
 	f.filters = nil
 }
 
-func (f *optionsFilter) buildVietnameseRegex(value string) string {
-	value = strings.ToLower(value)
-	value = tool.New().DeaccentVietnameseString(value)
-	replacer := strings.NewReplacer(
-		"a", "[aàáãảạăằắẳẵặâầấẩẫậ]",
-		"e", "[eèéẻẽẹêềếểễệ]",
-		"u", "[uùúủũụưừứửữự]",
-		"d", "[dđ]",
-		"o", "[oòóỏõọôồốổỗộơờớởỡợ]",
-		"i", "[iìíỉĩị]",
-		"y", "[yýỳỹỵỷ]")
-	return replacer.Replace(value)
+// NewSearchFilter matches value against the normalized shadow copy of the text field by.
+func NewSearchFilter(by string, method int, value string) bson.M {
+	return buildTextFilter(by, method, value, true)
+}
+
+// buildTextFilter matches a contains, starts-with or ends-with method. Search fields are matched against
+// their accent-free copy with case-sensitive regexes, so a prefix becomes an index range; substrings are
+// first narrowed through the stored trigrams. Other fields fall back to a case-insensitive regex.
+func buildTextFilter(by string, method int, value string, search bool) bson.M {
+	searchField, ok := models.SearchFields[by]
+	if !search || !ok {
+		return bson.M{by: bson.M{queryMethodMap[method]: primitive.Regex{Pattern: textPattern(method, regexp.QuoteMeta(value)), Options: "i"}}}
+	}
+	normalized := textsearch.New().Normalize(value)
+	filter := bson.M{searchField.Search: bson.M{queryMethodMap[method]: primitive.Regex{Pattern: textPattern(method, regexp.QuoteMeta(normalized))}}}
+	if grams := textsearch.New().Grams(normalized); method != QueryMethodStartsWith && searchField.Grams != "" && len(grams) > 0 {
+		filter[searchField.Grams] = bson.M{"$all": grams[:min(len(grams), maxFilterGrams)]}
+	}
+	return filter
+}
+
+func textPattern(method int, pattern string) string {
+	switch method {
+	case QueryMethodStartsWith:
+		return "^" + pattern
+	case QueryMethodEndsWith:
+		return pattern + "$"
+	}
+	return pattern
+}
+
+// setSearchFields refreshes the shadow copies of the given text fields that an update changes.
+func setSearchFields(data bson.M, fields ...string) {
+	for _, field := range fields {
+		if value, ok := data[field].(string); ok {
+			maps.Copy(data, models.SearchValues(field, value))
+		}
+	}
 }
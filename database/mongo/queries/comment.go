@@ -0,0 +1,203 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type CommentQuery interface {
+	Create(comment models.Comment) (newComment *models.Comment, err error)
+	GetByIdAndIssueId(id, issueId primitive.ObjectID, opts ...OptionsQuery) (comment *models.Comment, err error)
+	GetByIssueIdAndParentIdAfter(issueId, parentId, afterId primitive.ObjectID, limit int64) ([]models.Comment, error)
+	SearchText(search string, workspaceIds []primitive.ObjectID, limit int64) ([]TextScored[models.Comment], error)
+	UpdateByIdAndAuthorId(id, authorId primitive.ObjectID, data bson.M) (comment *models.Comment, err error)
+	IncreaseReplyCountById(id primitive.ObjectID, delta int64) error
+	DeleteById(id primitive.ObjectID) error
+	DeleteByParentId(parentId primitive.ObjectID) error
+	DeleteByIssueId(issueId primitive.ObjectID) error
+	DeleteByProjectId(projectId primitive.ObjectID) error
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type commentQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewComment(ctx context.Context) CommentQuery {
+	return &commentQuery{
+		collection: mongo.NewUtilityService().GetCommentCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *commentQuery) Create(data models.Comment) (*models.Comment, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	if data.Mentions == nil {
+		data.Mentions = make([]primitive.ObjectID, 0)
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("commentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *commentQuery) GetByIdAndIssueId(id, issueId primitive.ObjectID, opts ...OptionsQuery) (*models.Comment, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.Comment
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"_id": id, "issue_id": issueId}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Comment not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetByIdAndIssueId").Str("functionInline", "q.collection.FindOne").Msg("commentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+// GetByIssueIdAndParentIdAfter returns up to limit comments posted after afterId, oldest first.
+// A zero parentId lists top-level comments and a zero afterId starts from the beginning.
+func (q *commentQuery) GetByIssueIdAndParentIdAfter(issueId, parentId, afterId primitive.ObjectID, limit int64) ([]models.Comment, error) {
+	filter := bson.M{"issue_id": issueId, "parent_id": bson.M{"$exists": false}}
+	if !parentId.IsZero() {
+		filter["parent_id"] = parentId
+	}
+	if !afterId.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterId}
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var comments []models.Comment
+	optFind := options.Find().SetSort(bson.D{{Key: "_id", Value: SortTypeAsc}}).SetLimit(limit)
+	cursor, err := q.collection.Find(ctx, filter, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByIssueIdAndParentIdAfter").Str("functionInline", "q.collection.Find").Msg("commentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &comments); err != nil {
+		logger.Error().Err(err).Str("function", "GetByIssueIdAndParentIdAfter").Str("functionInline", "cursor.All").Msg("commentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return comments, nil
+}
+
+func (q *commentQuery) UpdateByIdAndAuthorId(id, authorId primitive.ObjectID, data bson.M) (*models.Comment, error) {
+	data["updated_at"] = time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var comment models.Comment
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "author_id": authorId}, bson.M{"$set": data}, optUpdate).Decode(&comment); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Comment not found"})
+		}
+		logger.Error().Err(err).Str("function", "UpdateByIdAndAuthorId").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("commentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &comment, nil
+}
+
+func (q *commentQuery) IncreaseReplyCountById(id primitive.ObjectID, delta int64) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"reply_count": delta}})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "IncreaseReplyCountById").Str("functionInline", "q.collection.UpdateOne").Msg("commentQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.MatchedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Comment not found"})
+	}
+	return nil
+}
+
+func (q *commentQuery) DeleteById(id primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteById").Str("functionInline", "q.collection.DeleteOne").Msg("commentQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.DeletedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Comment not found"})
+	}
+	return nil
+}
+
+func (q *commentQuery) DeleteByParentId(parentId primitive.ObjectID) error {
+	return q.deleteMany(bson.M{"parent_id": parentId}, "DeleteByParentId")
+}
+
+func (q *commentQuery) DeleteByIssueId(issueId primitive.ObjectID) error {
+	return q.deleteMany(bson.M{"issue_id": issueId}, "DeleteByIssueId")
+}
+
+func (q *commentQuery) DeleteByProjectId(projectId primitive.ObjectID) error {
+	return q.deleteMany(bson.M{"project_id": projectId}, "DeleteByProjectId")
+}
+
+func (q *commentQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	return q.deleteMany(bson.M{"workspace_id": workspaceId}, "DeleteByWorkspaceId")
+}
+
+func (q *commentQuery) deleteMany(filter bson.M, function string) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, filter); err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "q.collection.DeleteMany").Msg("commentQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *commentQuery) SearchText(search string, workspaceIds []primitive.ObjectID, limit int64) ([]TextScored[models.Comment], error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var comments []TextScored[models.Comment]
+	cursor, err := q.collection.Find(ctx, textSearchFilter(search, workspaceIds), textSearchOptions(limit, "body", "workspace_id", "project_id", "issue_id"))
+	if err != nil {
+		logger.Error().Err(err).Str("function", "SearchText").Str("functionInline", "q.collection.Find").Msg("commentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &comments); err != nil {
+		logger.Error().Err(err).Str("function", "SearchText").Str("functionInline", "cursor.All").Msg("commentQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return comments, nil
+}
@@ -0,0 +1,75 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type CounterQuery interface {
+	Next(id, workspaceId primitive.ObjectID) (seq int64, err error)
+	DeleteById(id primitive.ObjectID) error
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type counterQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewCounter(ctx context.Context) CounterQuery {
+	return &counterQuery{
+		collection: mongo.NewUtilityService().GetCounterCollection(),
+		context:    ctx,
+	}
+}
+
+// Next atomically increments and returns the counter, creating it on first use.
+// Run it in a transaction with the write that consumes the number so a failed write does not leave a gap.
+func (q *counterQuery) Next(id, workspaceId primitive.ObjectID) (int64, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var counter models.Counter
+	optUpdate := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{
+		"$inc":         bson.M{"seq": 1},
+		"$setOnInsert": bson.M{"workspace_id": workspaceId},
+	}, optUpdate).Decode(&counter); err != nil {
+		if isTransientTransactionError(err) {
+			return 0, err
+		}
+		logger.Error().Err(err).Str("function", "Next").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("counterQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return counter.Seq, nil
+}
+
+func (q *counterQuery) DeleteById(id primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteById").Str("functionInline", "q.collection.DeleteOne").Msg("counterQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *counterQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"workspace_id": workspaceId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceId").Str("functionInline", "q.collection.DeleteMany").Msg("counterQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type IssueQuery interface {
+	Create(issue models.Issue) (newIssue *models.Issue, err error)
+	GetByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, opts ...OptionsQuery) (issue *models.Issue, err error)
+	GetByKeyAndWorkspaceId(key string, workspaceId primitive.ObjectID, opts ...OptionsQuery) (issue *models.Issue, err error)
+	GetByIdOrKeyAndWorkspaceId(idOrKey string, workspaceId primitive.ObjectID, opts ...OptionsQuery) (issue *models.Issue, err error)
+	GetByIds(ids []primitive.ObjectID, opts ...OptionsQuery) ([]models.Issue, error)
+	TotalByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error)
+	GetByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.Issue, error)
+	SearchText(search string, workspaceIds []primitive.ObjectID, limit int64) ([]TextScored[models.Issue], error)
+	GetLastRankByWorkspaceIdAndStatus(workspaceId primitive.ObjectID, status string) (rank string, err error)
+	UpdateByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, data bson.M) (issue *models.Issue, err error)
+	UpdateByIdAndWorkspaceIdAndStatus(id, workspaceId primitive.ObjectID, status string, data bson.M) (issue *models.Issue, err error)
+	MoveByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, fromStatus, toStatus, rank string) (issue *models.Issue, err error)
+	UpdateRanks(issues []models.Issue, ranks []string) (matched int64, err error)
+	UpdateSprintByIdsAndWorkspaceId(ids []primitive.ObjectID, workspaceId primitive.ObjectID, filter bson.M, sprintId primitive.ObjectID) (int64, error)
+	UpdateSprintBySprintId(sprintId primitive.ObjectID, filter bson.M, targetSprintId primitive.ObjectID) (int64, error)
+	DeleteByIdAndWorkspaceId(id, workspaceId primitive.ObjectID) error
+	DeleteByProjectId(projectId primitive.ObjectID) error
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type issueQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewIssue(ctx context.Context) IssueQuery {
+	return &issueQuery{
+		collection: mongo.NewUtilityService().GetIssueCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *issueQuery) Create(data models.Issue) (*models.Issue, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	data.SetSearchFields()
+	if data.Labels == nil {
+		data.Labels = make([]string, 0)
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Issue already exists"})
+		}
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *issueQuery) GetByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, opts ...OptionsQuery) (*models.Issue, error) {
+	return q.findOne(bson.M{"_id": id, "workspace_id": workspaceId}, "GetByIdAndWorkspaceId", opts...)
+}
+
+func (q *issueQuery) GetByKeyAndWorkspaceId(key string, workspaceId primitive.ObjectID, opts ...OptionsQuery) (*models.Issue, error) {
+	return q.findOne(bson.M{"key": key, "workspace_id": workspaceId}, "GetByKeyAndWorkspaceId", opts...)
+}
+
+// GetByIdOrKeyAndWorkspaceId accepts either the issue id or its human-readable key such as "WEB-42".
+func (q *issueQuery) GetByIdOrKeyAndWorkspaceId(idOrKey string, workspaceId primitive.ObjectID, opts ...OptionsQuery) (*models.Issue, error) {
+	if id, err := primitive.ObjectIDFromHex(idOrKey); err == nil {
+		return q.GetByIdAndWorkspaceId(id, workspaceId, opts...)
+	}
+	return q.GetByKeyAndWorkspaceId(idOrKey, workspaceId, opts...)
+}
+
+func (q *issueQuery) GetByIds(ids []primitive.ObjectID, opts ...OptionsQuery) ([]models.Issue, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var issues []models.Issue
+	optFind := &options.FindOptions{Projection: opt.QueryOnlyField()}
+	cursor, err := q.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByIds").Str("functionInline", "q.collection.Find").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &issues); err != nil {
+		logger.Error().Err(err).Str("function", "GetByIds").Str("functionInline", "cursor.All").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return issues, nil
+}
+
+func (q *issueQuery) findOne(filter bson.M, function string, opts ...OptionsQuery) (*models.Issue, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.Issue
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, filter, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Issue not found"})
+		}
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "q.collection.FindOne").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+func (q *issueQuery) TotalByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error) {
+	filter["workspace_id"] = workspaceId
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	total, err := q.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return 0, err
+		}
+		logger.Error().Err(err).Str("function", "TotalByWorkspaceId").Str("functionInline", "q.collection.CountDocuments").Msg("issueQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return total, nil
+}
+
+func (q *issueQuery) GetByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.Issue, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	filter["workspace_id"] = workspaceId
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var issues []models.Issue
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Limit:      opt.QueryPaginationLimit(),
+		Skip:       opt.QueryPaginationSkip(),
+		Sort:       opt.QuerySort(),
+	}
+	cursor, err := q.collection.Find(ctx, filter, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByWorkspaceId").Str("functionInline", "q.collection.Find").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &issues); err != nil {
+		logger.Error().Err(err).Str("function", "GetByWorkspaceId").Str("functionInline", "cursor.All").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return issues, nil
+}
+
+// GetLastRankByWorkspaceIdAndStatus returns the rank of the bottom card of a board column, or "" when it is empty.
+func (q *issueQuery) GetLastRankByWorkspaceIdAndStatus(workspaceId primitive.ObjectID, status string) (string, error) {
+	var data models.Issue
+	optFind := options.FindOne().SetProjection(bson.M{"rank": 1}).SetSort(bson.D{{Key: "rank", Value: SortTypeDesc}})
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"workspace_id": workspaceId, "status": status}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return "", nil
+		}
+		if isTransientTransactionError(err) {
+			return "", err
+		}
+		logger.Error().Err(err).Str("function", "GetLastRankByWorkspaceIdAndStatus").Str("functionInline", "q.collection.FindOne").Msg("issueQuery")
+		return "", response.NewError(fiber.StatusInternalServerError)
+	}
+	return data.Rank, nil
+}
+
+func (q *issueQuery) UpdateByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, data bson.M) (*models.Issue, error) {
+	return q.update(bson.M{"_id": id, "workspace_id": workspaceId}, data, "UpdateByIdAndWorkspaceId", response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Issue not found"}))
+}
+
+// UpdateByIdAndWorkspaceIdAndStatus only applies while the issue is still in status, so a status
+// change checked against the workflow never races past a concurrent one.
+func (q *issueQuery) UpdateByIdAndWorkspaceIdAndStatus(id, workspaceId primitive.ObjectID, status string, data bson.M) (*models.Issue, error) {
+	return q.update(bson.M{"_id": id, "workspace_id": workspaceId, "status": status}, data, "UpdateByIdAndWorkspaceIdAndStatus", response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Issue was changed by someone else, reload it and retry"}))
+}
+
+func (q *issueQuery) update(filter, data bson.M, function string, notMatched error) (*models.Issue, error) {
+	data["updated_at"] = time.Now()
+	setSearchFields(data, "title", "description")
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var issue models.Issue
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := q.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": data}, optUpdate).Decode(&issue); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, notMatched
+		}
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "q.collection.FindOneAndUpdate").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &issue, nil
+}
+
+// MoveByIdAndWorkspaceId sets the status and rank in one update. It only applies while the issue is
+// still in fromStatus, so a move never races past a concurrent status change.
+func (q *issueQuery) MoveByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, fromStatus, toStatus, rank string) (*models.Issue, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var issue models.Issue
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	filter := bson.M{"_id": id, "workspace_id": workspaceId, "status": fromStatus}
+	update := bson.M{"$set": bson.M{"status": toStatus, "rank": rank, "updated_at": time.Now()}}
+	if err := q.collection.FindOneAndUpdate(ctx, filter, update, optUpdate).Decode(&issue); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Issue was changed by someone else, reload the board and retry"})
+		}
+		logger.Error().Err(err).Str("function", "MoveByIdAndWorkspaceId").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &issue, nil
+}
+
+// UpdateRanks assigns ranks[i] to issues[i] in a single bulk write. Each update only applies while the
+// issue still has the status and rank it was read with, so a card moved meanwhile is left alone; the
+// number of issues that matched tells the caller whether every update went through.
+func (q *issueQuery) UpdateRanks(issues []models.Issue, ranks []string) (int64, error) {
+	if len(issues) == 0 {
+		return 0, nil
+	}
+	writes := make([]mongoDriver.WriteModel, len(issues))
+	for i := range issues {
+		filter := bson.M{"_id": issues[i].Id, "status": issues[i].Status, "rank": issues[i].Rank}
+		if issues[i].Rank == "" {
+			filter["rank"] = bson.M{"$in": bson.A{"", nil}}
+		}
+		writes[i] = mongoDriver.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": bson.M{"rank": ranks[i]}})
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		logger.Error().Err(err).Str("function", "UpdateRanks").Str("functionInline", "q.collection.BulkWrite").Msg("issueQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return result.MatchedCount, nil
+}
+
+// UpdateSprintByIdsAndWorkspaceId moves the given issues into sprintId, or back to the backlog when it is zero.
+func (q *issueQuery) UpdateSprintByIdsAndWorkspaceId(ids []primitive.ObjectID, workspaceId primitive.ObjectID, filter bson.M, sprintId primitive.ObjectID) (int64, error) {
+	filter["_id"] = bson.M{"$in": ids}
+	filter["workspace_id"] = workspaceId
+	return q.updateSprint(filter, sprintId, "UpdateSprintByIdsAndWorkspaceId")
+}
+
+// UpdateSprintBySprintId moves the issues of a sprint into targetSprintId, or back to the backlog when it is zero.
+func (q *issueQuery) UpdateSprintBySprintId(sprintId primitive.ObjectID, filter bson.M, targetSprintId primitive.ObjectID) (int64, error) {
+	filter["sprint_id"] = sprintId
+	return q.updateSprint(filter, targetSprintId, "UpdateSprintBySprintId")
+}
+
+func (q *issueQuery) updateSprint(filter bson.M, sprintId primitive.ObjectID, function string) (int64, error) {
+	update := bson.M{"$set": bson.M{"sprint_id": sprintId, "updated_at": time.Now()}}
+	if sprintId.IsZero() {
+		update = bson.M{"$set": bson.M{"updated_at": time.Now()}, "$unset": bson.M{"sprint_id": ""}}
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return 0, err
+		}
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "q.collection.UpdateMany").Msg("issueQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (q *issueQuery) DeleteByIdAndWorkspaceId(id, workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.DeleteOne(ctx, bson.M{"_id": id, "workspace_id": workspaceId})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteByIdAndWorkspaceId").Str("functionInline", "q.collection.DeleteOne").Msg("issueQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.DeletedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Issue not found"})
+	}
+	return nil
+}
+
+func (q *issueQuery) DeleteByProjectId(projectId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"project_id": projectId}); err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteByProjectId").Str("functionInline", "q.collection.DeleteMany").Msg("issueQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *issueQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"workspace_id": workspaceId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceId").Str("functionInline", "q.collection.DeleteMany").Msg("issueQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *issueQuery) SearchText(search string, workspaceIds []primitive.ObjectID, limit int64) ([]TextScored[models.Issue], error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var issues []TextScored[models.Issue]
+	cursor, err := q.collection.Find(ctx, textSearchFilter(search, workspaceIds), textSearchOptions(limit, "key", "title", "description", "workspace_id", "project_id"))
+	if err != nil {
+		logger.Error().Err(err).Str("function", "SearchText").Str("functionInline", "q.collection.Find").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &issues); err != nil {
+		logger.Error().Err(err).Str("function", "SearchText").Str("functionInline", "cursor.All").Msg("issueQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return issues, nil
+}
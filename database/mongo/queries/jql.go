@@ -0,0 +1,223 @@
+package queries
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"jira-clone-api/utilities/jql"
+)
+
+// ErrJQLValueNotFound is returned by JQLField.Resolve for values that name nothing, such as an
+// unknown username; the compiler reports it at the position of the value.
+var ErrJQLValueNotFound = errors.New("value does not exist")
+
+// ErrJQLArgumentsInvalid is returned by a JQLFunction called with arguments it does not accept.
+var ErrJQLArgumentsInvalid = errors.New("invalid function arguments")
+
+var jqlRelativeDateRegex = regexp.MustCompile(`^([+-]?\d+)([mhdw])$`)
+
+var jqlOperatorMethodMap = map[string]int{
+	jql.OperatorEqual:              QueryMethodEqual,
+	jql.OperatorNotEqual:           QueryMethodNotEqual,
+	jql.OperatorGreaterThan:        QueryMethodGreaterThan,
+	jql.OperatorGreaterThanOrEqual: QueryMethodGreaterThanOrEqual,
+	jql.OperatorLessThan:           QueryMethodLessThan,
+	jql.OperatorLessThanOrEqual:    QueryMethodLessThanOrEqual,
+	jql.OperatorIn:                 QueryMethodIn,
+	jql.OperatorNotIn:              QueryMethodNotIn,
+}
+
+// JQLField exposes a stored field to JQL. Resolve, when set, turns a literal such as a username into
+// the stored value and may return a slice, which makes = and != match any of its elements; otherwise
+// literals are coerced according to Type. Search makes ~ match the normalized copy of the field.
+type JQLField struct {
+	By       string
+	Type     int
+	Sortable bool
+	Search   bool
+	Resolve  func(value string) (interface{}, error)
+}
+
+// JQLFunction evaluates a function call such as currentUser() to a value or a slice of values.
+type JQLFunction func(args []string) (interface{}, error)
+
+// JQLCompiler turns parsed queries into Mongo filters and sorts. Field and function names are
+// matched case-insensitively and must be registered in lower case.
+type JQLCompiler struct {
+	Fields    map[string]JQLField
+	Functions map[string]JQLFunction
+}
+
+func (c JQLCompiler) Compile(query *jql.Query) (bson.M, bson.D, error) {
+	filter := bson.M{}
+	if query.Where != nil {
+		var err error
+		if filter, err = c.compileNode(query.Where); err != nil {
+			return nil, nil, err
+		}
+	}
+	sort := bson.D{}
+	for _, sortField := range query.OrderBy {
+		field, ok := c.Fields[strings.ToLower(sortField.Field)]
+		if !ok || !field.Sortable {
+			return nil, nil, jql.NewError(sortField.Position, "field %q cannot be sorted", sortField.Field)
+		}
+		sortType := SortTypeAsc
+		if sortField.Descending {
+			sortType = SortTypeDesc
+		}
+		sort = append(sort, bson.E{Key: field.By, Value: sortType})
+	}
+	return filter, sort, nil
+}
+
+func (c JQLCompiler) compileNode(node jql.Node) (bson.M, error) {
+	switch node := node.(type) {
+	case *jql.BinaryExpr:
+		operator := "$and"
+		if node.Operator == jql.OperatorOr {
+			operator = "$or"
+		}
+		conditions := make(bson.A, 0, 2)
+		for _, child := range []jql.Node{node.Left, node.Right} {
+			condition, err := c.compileNode(child)
+			if err != nil {
+				return nil, err
+			}
+			// Flatten chains such as a AND b AND c into a single $and.
+			if nested, ok := condition[operator].(bson.A); ok && len(condition) == 1 {
+				conditions = append(conditions, nested...)
+			} else {
+				conditions = append(conditions, condition)
+			}
+		}
+		return bson.M{operator: conditions}, nil
+	case *jql.NotExpr:
+		condition, err := c.compileNode(node.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$nor": bson.A{condition}}, nil
+	case *jql.Clause:
+		return c.compileClause(node)
+	}
+	return nil, jql.NewError(node.Pos(), "unsupported expression")
+}
+
+func (c JQLCompiler) compileClause(clause *jql.Clause) (bson.M, error) {
+	field, ok := c.Fields[strings.ToLower(clause.Field)]
+	if !ok {
+		return nil, jql.NewError(clause.Position, "field %q does not exist", clause.Field)
+	}
+	switch clause.Operator {
+	case jql.OperatorIs:
+		return bson.M{field.By: bson.M{queryMethodMap[QueryMethodIn]: bson.A{nil, "", bson.A{}}}}, nil
+	case jql.OperatorIsNot:
+		return bson.M{field.By: bson.M{queryMethodMap[QueryMethodNotIn]: bson.A{nil, "", bson.A{}}}}, nil
+	case jql.OperatorContains, jql.OperatorNotContains:
+		value := clause.Values[0]
+		if field.Type != FieldTypeString || value.Kind != jql.ValueLiteral || value.Text == "" {
+			return nil, jql.NewError(clause.Position, "operator %s is not supported for field %q", clause.Operator, clause.Field)
+		}
+		filter := buildTextFilter(field.By, QueryMethodContains, value.Text, field.Search)
+		if clause.Operator == jql.OperatorNotContains {
+			return bson.M{"$nor": bson.A{filter}}, nil
+		}
+		return filter, nil
+	}
+	method, ok := jqlOperatorMethodMap[clause.Operator]
+	if !ok {
+		return nil, jql.NewError(clause.Position, "operator %s is not supported", clause.Operator)
+	}
+	values := make(bson.A, 0, len(clause.Values))
+	for _, value := range clause.Values {
+		if value.Kind == jql.ValueEmpty {
+			return nil, jql.NewError(value.Position, "use IS EMPTY or IS NOT EMPTY instead")
+		}
+		resolved, err := c.resolveValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		if many, ok := asSlice(resolved); ok {
+			values = append(values, many...)
+			if method == QueryMethodEqual {
+				method = QueryMethodIn
+			} else if method == QueryMethodNotEqual {
+				method = QueryMethodNotIn
+			} else if method != QueryMethodIn && method != QueryMethodNotIn {
+				return nil, jql.NewError(value.Position, "operator %s needs a single value", clause.Operator)
+			}
+			continue
+		}
+		values = append(values, resolved)
+	}
+	if method == QueryMethodIn || method == QueryMethodNotIn {
+		return bson.M{field.By: bson.M{queryMethodMap[method]: values}}, nil
+	}
+	return bson.M{field.By: bson.M{queryMethodMap[method]: values[0]}}, nil
+}
+
+func (c JQLCompiler) resolveValue(field JQLField, value jql.Value) (interface{}, error) {
+	var (
+		resolved interface{}
+		err      error
+	)
+	if value.Kind == jql.ValueFunction {
+		function, ok := c.Functions[strings.ToLower(value.Text)]
+		if !ok {
+			return nil, jql.NewError(value.Position, "function %s() does not exist", value.Text)
+		}
+		args := make([]string, len(value.Args))
+		for i, arg := range value.Args {
+			args[i] = arg.Text
+		}
+		resolved, err = function(args)
+	} else if field.Resolve != nil {
+		resolved, err = field.Resolve(value.Text)
+	} else if resolved, err = coerceJQLValue(field.Type, value.Text); err != nil {
+		return nil, jql.NewError(value.Position, "value %q has the wrong type", value.Text)
+	}
+	if err != nil {
+		var jqlErr *jql.Error
+		if errors.As(err, &jqlErr) {
+			return nil, err
+		}
+		if errors.Is(err, ErrJQLValueNotFound) {
+			return nil, jql.NewError(value.Position, "value %q does not exist", value.Text)
+		}
+		if errors.Is(err, ErrJQLArgumentsInvalid) {
+			return nil, jql.NewError(value.Position, "invalid arguments for %s()", value.Text)
+		}
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// coerceJQLValue is ListField coercion plus relative dates such as -7d or 2w, counted from now.
+func coerceJQLValue(fieldType int, raw string) (interface{}, error) {
+	if fieldType == FieldTypeDate {
+		if match := jqlRelativeDateRegex.FindStringSubmatch(raw); match != nil {
+			amount, _ := strconv.Atoi(match[1])
+			unit := map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[match[2]]
+			return time.Now().Add(time.Duration(amount) * unit), nil
+		}
+	}
+	return ListField{Type: fieldType}.coerce(raw)
+}
+
+func asSlice(value interface{}) (bson.A, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	values := make(bson.A, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, true
+}
@@ -0,0 +1,161 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/utilities/jql"
+)
+
+// CompileIssueJQL parses input and compiles it against the issue fields of workspaceId, with
+// currentUser() standing for userId. Syntax and compile errors come back as a 400 carrying the
+// line and column of the problem.
+func CompileIssueJQL(ctx context.Context, workspaceId, userId primitive.ObjectID, input string) (bson.M, bson.D, error) {
+	query, err := jql.New().Parse(input)
+	if err == nil {
+		var (
+			filter bson.M
+			sort   bson.D
+		)
+		if filter, sort, err = newIssueJQLCompiler(ctx, workspaceId, userId).Compile(query); err == nil {
+			return filter, sort, nil
+		}
+	}
+	var jqlErr *jql.Error
+	if errors.As(err, &jqlErr) {
+		return nil, nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data:       jqlErr,
+			ReturnCode: constants.ReturnCodeJQLInvalid,
+		})
+	}
+	return nil, nil, err
+}
+
+func newIssueJQLCompiler(ctx context.Context, workspaceId, userId primitive.ObjectID) JQLCompiler {
+	var workflow *models.Workflow
+	resolveUser := func(value string) (interface{}, error) {
+		if id, err := primitive.ObjectIDFromHex(value); err == nil {
+			return id, nil
+		}
+		userOption := NewOptions()
+		userOption.SetOnlyFields("_id")
+		user, err := NewUser(ctx).GetByUsername(value, userOption)
+		if err != nil {
+			return nil, notFoundAsJQLValue(err)
+		}
+		return user.Id, nil
+	}
+	resolveProject := func(value string) (interface{}, error) {
+		if id, err := primitive.ObjectIDFromHex(value); err == nil {
+			return id, nil
+		}
+		projectOption := NewOptions()
+		projectOption.SetOnlyFields("_id")
+		project, err := NewProject(ctx).GetByKeyAndWorkspaceId(strings.ToUpper(value), workspaceId, projectOption)
+		if err != nil {
+			return nil, notFoundAsJQLValue(err)
+		}
+		return project.Id, nil
+	}
+	resolveStatusCategory := func(value string) (interface{}, error) {
+		category := strings.NewReplacer(" ", "", "_", "").Replace(strings.ToLower(value))
+		for _, known := range []string{constants.WorkflowCategoryTodo, constants.WorkflowCategoryInProgress, constants.WorkflowCategoryDone} {
+			if category != strings.ReplaceAll(known, "_", "") {
+				continue
+			}
+			if workflow == nil {
+				var err error
+				if workflow, err = NewWorkflow(ctx).GetByWorkspaceId(workspaceId); err != nil {
+					return nil, err
+				}
+			}
+			return workflow.StatusesInCategory(known), nil
+		}
+		return nil, ErrJQLValueNotFound
+	}
+	return JQLCompiler{
+		Fields: map[string]JQLField{
+			"project":        {By: "project_id", Type: FieldTypeObjectId, Resolve: resolveProject},
+			"key":            {By: "key", Type: FieldTypeString, Resolve: upperJQLValue},
+			"issuekey":       {By: "key", Type: FieldTypeString, Resolve: upperJQLValue},
+			"summary":        {By: "title", Type: FieldTypeString, Sortable: true, Search: true},
+			"title":          {By: "title", Type: FieldTypeString, Sortable: true, Search: true},
+			"description":    {By: "description", Type: FieldTypeString, Search: true},
+			"status":         {By: "status", Type: FieldTypeString, Sortable: true},
+			"statuscategory": {By: "status", Type: FieldTypeString, Resolve: resolveStatusCategory},
+			"priority":       {By: "priority", Type: FieldTypeString, Resolve: lowerJQLValue},
+			"labels":         {By: "labels", Type: FieldTypeString},
+			"label":          {By: "labels", Type: FieldTypeString},
+			"assignee":       {By: "assignee_id", Type: FieldTypeObjectId, Resolve: resolveUser},
+			"reporter":       {By: "reporter_id", Type: FieldTypeObjectId, Resolve: resolveUser},
+			"sprint":         {By: "sprint_id", Type: FieldTypeObjectId},
+			"created":        {By: "created_at", Type: FieldTypeDate, Sortable: true},
+			"updated":        {By: "updated_at", Type: FieldTypeDate, Sortable: true},
+			"due":            {By: "due_date", Type: FieldTypeDate, Sortable: true},
+			"duedate":        {By: "due_date", Type: FieldTypeDate, Sortable: true},
+			"rank":           {By: "rank", Type: FieldTypeString, Sortable: true},
+		},
+		Functions: map[string]JQLFunction{
+			"currentuser": func(args []string) (interface{}, error) {
+				if len(args) > 0 {
+					return nil, ErrJQLArgumentsInvalid
+				}
+				return userId, nil
+			},
+			"now": func(args []string) (interface{}, error) {
+				if len(args) > 0 {
+					return nil, ErrJQLArgumentsInvalid
+				}
+				return time.Now(), nil
+			},
+			"startofday": func(args []string) (interface{}, error) {
+				if len(args) > 0 {
+					return nil, ErrJQLArgumentsInvalid
+				}
+				year, month, day := time.Now().Date()
+				return time.Date(year, month, day, 0, 0, 0, 0, time.Local), nil
+			},
+			"opensprints": func(args []string) (interface{}, error) {
+				if len(args) > 0 {
+					return nil, ErrJQLArgumentsInvalid
+				}
+				sprintOption := NewOptions()
+				sprintOption.SetOnlyFields("_id")
+				sprints, err := NewSprint(ctx).GetByWorkspaceId(workspaceId, bson.M{"state": constants.SprintStateActive}, sprintOption)
+				if err != nil {
+					return nil, err
+				}
+				ids := make([]primitive.ObjectID, len(sprints))
+				for i := range sprints {
+					ids[i] = sprints[i].Id
+				}
+				return ids, nil
+			},
+		},
+	}
+}
+
+func upperJQLValue(value string) (interface{}, error) {
+	return strings.ToUpper(value), nil
+}
+
+func lowerJQLValue(value string) (interface{}, error) {
+	return strings.ToLower(value), nil
+}
+
+// notFoundAsJQLValue keeps lookups of unknown names from surfacing as a 404 of the search itself.
+func notFoundAsJQLValue(err error) error {
+	var responseErr *response.Error
+	if errors.As(err, &responseErr) && responseErr.Code == fiber.StatusNotFound {
+		return ErrJQLValueNotFound
+	}
+	return err
+}
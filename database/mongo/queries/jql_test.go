@@ -0,0 +1,135 @@
+package queries
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/utilities/jql"
+)
+
+// testJQLCompiler resolves users and sprints from fixed maps, so compiling never reaches Mongo.
+// alice and the team stand for a single id and for several ids.
+func testJQLCompiler() JQLCompiler {
+	users := map[string]interface{}{
+		"alice": "user-alice",
+		"team":  []string{"user-bob", "user-carol"},
+	}
+	resolveUser := func(value string) (interface{}, error) {
+		if user, ok := users[value]; ok {
+			return user, nil
+		}
+		return nil, ErrJQLValueNotFound
+	}
+	return JQLCompiler{
+		Fields: map[string]JQLField{
+			"status":   {By: "status", Type: FieldTypeString, Sortable: true},
+			"summary":  {By: "title", Type: FieldTypeString},
+			"points":   {By: "points", Type: FieldTypeNumber},
+			"assignee": {By: "assignee_id", Type: FieldTypeString, Resolve: resolveUser},
+			"sprint":   {By: "sprint_id", Type: FieldTypeString},
+			"created":  {By: "created_at", Type: FieldTypeDate, Sortable: true},
+		},
+		Functions: map[string]JQLFunction{
+			"currentuser": func(args []string) (interface{}, error) {
+				return "user-alice", nil
+			},
+			"opensprints": func(args []string) (interface{}, error) {
+				if len(args) > 0 {
+					return nil, ErrJQLArgumentsInvalid
+				}
+				return []string{"sprint-1", "sprint-2"}, nil
+			},
+		},
+	}
+}
+
+func compileJQL(t *testing.T, input string) (bson.M, bson.D, error) {
+	t.Helper()
+	query, err := jql.New().Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testJQLCompiler().Compile(query)
+}
+
+func TestJQLCompile(t *testing.T) {
+	done := bson.M{"status": bson.M{"$eq": "Done"}}
+	open := bson.M{"status": bson.M{"$eq": "Open"}}
+	alice := bson.M{"assignee_id": bson.M{"$eq": "user-alice"}}
+	tests := []struct {
+		input  string
+		filter bson.M
+		sort   bson.D
+	}{
+		{`status = Done`, done, bson.D{}},
+		{`status = Done OR status = Open AND assignee = alice`, bson.M{"$or": bson.A{done, bson.M{"$and": bson.A{open, alice}}}}, bson.D{}},
+		{`(status = Done OR status = Open) AND assignee = alice`, bson.M{"$and": bson.A{bson.M{"$or": bson.A{done, open}}, alice}}, bson.D{}},
+		{`status = Done AND status = Open AND assignee = alice`, bson.M{"$and": bson.A{done, open, alice}}, bson.D{}},
+		{`status = Done OR (status = Open OR assignee = alice)`, bson.M{"$or": bson.A{done, open, alice}}, bson.D{}},
+		{`NOT status = Done`, bson.M{"$nor": bson.A{done}}, bson.D{}},
+		{`NOT (status = Done OR status = Open)`, bson.M{"$nor": bson.A{bson.M{"$or": bson.A{done, open}}}}, bson.D{}},
+		{`status != Done`, bson.M{"status": bson.M{"$ne": "Done"}}, bson.D{}},
+		{`status IN (Done, Open)`, bson.M{"status": bson.M{"$in": bson.A{"Done", "Open"}}}, bson.D{}},
+		{`status NOT IN (Done, Open)`, bson.M{"status": bson.M{"$nin": bson.A{"Done", "Open"}}}, bson.D{}},
+		{`assignee IS EMPTY`, bson.M{"assignee_id": bson.M{"$in": bson.A{nil, "", bson.A{}}}}, bson.D{}},
+		{`assignee IS NOT EMPTY`, bson.M{"assignee_id": bson.M{"$nin": bson.A{nil, "", bson.A{}}}}, bson.D{}},
+		{`summary !~ login`, bson.M{"$nor": bson.A{bson.M{"title": bson.M{"$regex": primitive.Regex{Pattern: "login", Options: "i"}}}}}, bson.D{}},
+		{`points >= 3`, bson.M{"points": bson.M{"$gte": int64(3)}}, bson.D{}},
+		{`assignee = currentUser()`, alice, bson.D{}},
+		// Slice-valued resolvers and functions are flattened into $in and $nin.
+		{`assignee = team`, bson.M{"assignee_id": bson.M{"$in": bson.A{"user-bob", "user-carol"}}}, bson.D{}},
+		{`assignee != team`, bson.M{"assignee_id": bson.M{"$nin": bson.A{"user-bob", "user-carol"}}}, bson.D{}},
+		{`assignee IN (alice, team)`, bson.M{"assignee_id": bson.M{"$in": bson.A{"user-alice", "user-bob", "user-carol"}}}, bson.D{}},
+		{`sprint IN openSprints()`, bson.M{"sprint_id": bson.M{"$in": bson.A{"sprint-1", "sprint-2"}}}, bson.D{}},
+		{`sprint NOT IN openSprints()`, bson.M{"sprint_id": bson.M{"$nin": bson.A{"sprint-1", "sprint-2"}}}, bson.D{}},
+		{`ORDER BY created DESC, STATUS`, bson.M{}, bson.D{{Key: "created_at", Value: SortTypeDesc}, {Key: "status", Value: SortTypeAsc}}},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			filter, sort, err := compileJQL(t, test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(filter, test.filter) {
+				t.Errorf("filter: got %v, want %v", filter, test.filter)
+			}
+			if !reflect.DeepEqual(sort, test.sort) {
+				t.Errorf("sort: got %v, want %v", sort, test.sort)
+			}
+		})
+	}
+}
+
+func TestJQLCompileErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		line    int
+		column  int
+		message string
+	}{
+		{`unknown = 1`, 1, 1, `field "unknown" does not exist`},
+		{`status = Done AND assignee = nobody`, 1, 30, `value "nobody" does not exist`},
+		{`assignee > team`, 1, 12, "operator > needs a single value"},
+		{`assignee = EMPTY`, 1, 12, "use IS EMPTY or IS NOT EMPTY instead"},
+		{`points = many`, 1, 10, `value "many" has the wrong type`},
+		{`points ~ 3`, 1, 1, `operator ~ is not supported for field "points"`},
+		{`sprint IN closedSprints()`, 1, 11, "function closedSprints() does not exist"},
+		{`sprint IN openSprints(all)`, 1, 11, "invalid arguments for openSprints()"},
+		{`ORDER BY summary`, 1, 10, `field "summary" cannot be sorted`},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, _, err := compileJQL(t, test.input)
+			var jqlErr *jql.Error
+			if !errors.As(err, &jqlErr) {
+				t.Fatalf("got %v, want a jql error", err)
+			}
+			if jqlErr.Line != test.line || jqlErr.Column != test.column || jqlErr.Message != test.message {
+				t.Fatalf("got %d:%d %q, want %d:%d %q", jqlErr.Line, jqlErr.Column, jqlErr.Message, test.line, test.column, test.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package queries
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+)
+
+// Value types list query values are coerced to before they reach Mongo.
+const (
+	FieldTypeString = iota
+	FieldTypeObjectId
+	FieldTypeDate
+	FieldTypeNumber
+)
+
+const (
+	listFilterMaxCount  = 10
+	listFilterMaxValues = 100
+)
+
+var listMethodMap = map[string]int{
+	"eq":         QueryMethodEqual,
+	"ne":         QueryMethodNotEqual,
+	"contains":   QueryMethodContains,
+	"startswith": QueryMethodStartsWith,
+	"endswith":   QueryMethodEndsWith,
+	"gt":         QueryMethodGreaterThan,
+	"gte":        QueryMethodGreaterThanOrEqual,
+	"lt":         QueryMethodLessThan,
+	"lte":        QueryMethodLessThanOrEqual,
+	"in":         QueryMethodIn,
+	"nin":        QueryMethodNotIn,
+}
+
+// ListField describes how a list endpoint exposes one stored field. By defaults to the public name.
+// Search makes the text methods match the normalized copy of the field, see models.SearchFields.
+type ListField struct {
+	By         string
+	Type       int
+	Filterable bool
+	Sortable   bool
+	Search     bool
+}
+
+// ListFields is the whitelist of a list endpoint, keyed by the name used in the query string.
+type ListFields map[string]ListField
+
+// ParseFilters turns "field:method:value" expressions such as "created_at:gte:2024-01-01" into filters.
+// The in and nin methods take comma separated values.
+func (f ListFields) ParseFilters(expressions []string) ([]Filter, error) {
+	if len(expressions) > listFilterMaxCount {
+		return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrValueIsNotAccepted})
+	}
+	filters := make([]Filter, 0, len(expressions))
+	for _, expression := range expressions {
+		parts := strings.SplitN(expression, ":", 3)
+		if len(parts) != 3 {
+			return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrValueIsNotAccepted})
+		}
+		field, ok := f[parts[0]]
+		if !ok || !field.Filterable {
+			return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrQueryByFieldNotAllowed})
+		}
+		method, ok := listMethodMap[strings.ToLower(parts[1])]
+		if !ok {
+			return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrQueryMethodNotAllowed})
+		}
+		value, err := field.parseValue(method, parts[2])
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, Filter{Value: value, By: field.by(parts[0]), Method: method, Search: field.Search})
+	}
+	return filters, nil
+}
+
+// ParseSort turns "-created_at,name" into sort keys; a leading "-" sorts descending.
+func (f ListFields) ParseSort(expression string) (bson.D, error) {
+	sort := bson.D{}
+	if expression == "" {
+		return sort, nil
+	}
+	for _, name := range strings.Split(expression, ",") {
+		sortType := SortTypeAsc
+		if strings.HasPrefix(name, "-") {
+			name, sortType = name[1:], SortTypeDesc
+		}
+		field, ok := f[name]
+		if !ok || !field.Sortable {
+			return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrQueryByFieldNotAllowed})
+		}
+		sort = append(sort, bson.E{Key: field.by(name), Value: sortType})
+	}
+	return sort, nil
+}
+
+// BuildFilter parses expressions and merges them into filter, which may already hold conditions.
+func (f ListFields) BuildFilter(filter bson.M, expressions []string) error {
+	filters, err := f.ParseFilters(expressions)
+	if err != nil || len(filters) == 0 {
+		return err
+	}
+	optionsFilter := NewOptionsFilter()
+	if err = optionsFilter.AddListFilter(filters); err != nil {
+		return err
+	}
+	filter["$and"] = optionsFilter.BuildMongoFilterWithAndCondition()["$and"]
+	return nil
+}
+
+func (field ListField) by(name string) string {
+	if field.By != "" {
+		return field.By
+	}
+	return name
+}
+
+func (field ListField) parseValue(method int, raw string) (interface{}, error) {
+	switch method {
+	case QueryMethodContains, QueryMethodStartsWith, QueryMethodEndsWith:
+		if field.Type != FieldTypeString || raw == "" {
+			return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrQueryMethodNotAllowed})
+		}
+		return raw, nil
+	case QueryMethodIn, QueryMethodNotIn:
+		raws := strings.Split(raw, ",")
+		if len(raws) > listFilterMaxValues {
+			return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrValueIsNotAccepted})
+		}
+		values := make([]interface{}, len(raws))
+		for i := range raws {
+			value, err := field.coerce(raws[i])
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+	return field.coerce(raw)
+}
+
+func (field ListField) coerce(raw string) (interface{}, error) {
+	switch field.Type {
+	case FieldTypeObjectId:
+		if id, err := primitive.ObjectIDFromHex(raw); err == nil {
+			return id, nil
+		}
+	case FieldTypeDate:
+		if date, err := time.Parse(time.RFC3339, raw); err == nil {
+			return date, nil
+		}
+		if date, err := time.Parse(time.DateOnly, raw); err == nil {
+			return date, nil
+		}
+	case FieldTypeNumber:
+		if number, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return number, nil
+		}
+		if number, err := strconv.ParseFloat(raw, 64); err == nil {
+			return number, nil
+		}
+	default:
+		return raw, nil
+	}
+	return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrFieldWrongType})
+}
@@ -0,0 +1,93 @@
+package queries
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type LoginAttemptQuery interface {
+	GetLockedByKeys(keys []string) (loginAttempts []models.LoginAttempt, err error)
+	IncreaseFailuresByKey(key string, window time.Duration) (loginAttempt *models.LoginAttempt, err error)
+	LockByKey(key string, lockedUntil time.Time) error
+	DeleteByKey(key string) error
+}
+
+type loginAttemptQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewLoginAttempt(ctx context.Context) LoginAttemptQuery {
+	return &loginAttemptQuery{
+		collection: mongo.NewUtilityService().GetLoginAttemptCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *loginAttemptQuery) GetLockedByKeys(keys []string) ([]models.LoginAttempt, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var loginAttempts []models.LoginAttempt
+	cursor, err := q.collection.Find(ctx, bson.M{"key": bson.M{"$in": keys}, "locked_until": bson.M{"$gt": time.Now()}})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetLockedByKeys").Str("functionInline", "q.collection.Find").Msg("loginAttemptQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &loginAttempts); err != nil {
+		logger.Error().Err(err).Str("function", "GetLockedByKeys").Str("functionInline", "cursor.All").Msg("loginAttemptQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return loginAttempts, nil
+}
+
+// IncreaseFailuresByKey counts one more failure for key, creating its counter when needed, and keeps
+// the counter alive for window after this failure.
+func (q *loginAttemptQuery) IncreaseFailuresByKey(key string, window time.Duration) (*models.LoginAttempt, error) {
+	currentTime := time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var loginAttempt models.LoginAttempt
+	optUpdate := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{"key": key}, bson.M{
+		"$inc":         bson.M{"failures": 1},
+		"$set":         bson.M{"updated_at": currentTime},
+		"$max":         bson.M{"expired_at": currentTime.Add(window)},
+		"$setOnInsert": bson.M{"created_at": currentTime},
+	}, optUpdate).Decode(&loginAttempt); err != nil {
+		logger.Error().Err(err).Str("function", "IncreaseFailuresByKey").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("loginAttemptQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &loginAttempt, nil
+}
+
+// LockByKey locks key until lockedUntil, keeping the counter at least that long.
+func (q *loginAttemptQuery) LockByKey(key string, lockedUntil time.Time) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.UpdateOne(ctx, bson.M{"key": key}, bson.M{
+		"$set": bson.M{"locked_until": lockedUntil, "updated_at": time.Now()},
+		"$max": bson.M{"expired_at": lockedUntil},
+	}); err != nil {
+		logger.Error().Err(err).Str("function", "LockByKey").Str("functionInline", "q.collection.UpdateOne").Msg("loginAttemptQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *loginAttemptQuery) DeleteByKey(key string) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteOne(ctx, bson.M{"key": key}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByKey").Str("functionInline", "q.collection.DeleteOne").Msg("loginAttemptQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type ProjectQuery interface {
+	Create(project models.Project) (newProject *models.Project, err error)
+	GetByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, opts ...OptionsQuery) (project *models.Project, err error)
+	GetByKeyAndWorkspaceId(key string, workspaceId primitive.ObjectID, opts ...OptionsQuery) (project *models.Project, err error)
+	TotalByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error)
+	GetByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.Project, error)
+	SearchText(search string, workspaceIds []primitive.ObjectID, limit int64) ([]TextScored[models.Project], error)
+	UpdateByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, data bson.M) (project *models.Project, err error)
+	DeleteByIdAndWorkspaceId(id, workspaceId primitive.ObjectID) error
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type projectQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewProject(ctx context.Context) ProjectQuery {
+	return &projectQuery{
+		collection: mongo.NewUtilityService().GetProjectCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *projectQuery) Create(data models.Project) (*models.Project, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	data.SetSearchFields()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Project key already exists"})
+		}
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("projectQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *projectQuery) GetByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, opts ...OptionsQuery) (*models.Project, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.Project
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"_id": id, "workspace_id": workspaceId}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Project not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetByIdAndWorkspaceId").Str("functionInline", "q.collection.FindOne").Msg("projectQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+func (q *projectQuery) GetByKeyAndWorkspaceId(key string, workspaceId primitive.ObjectID, opts ...OptionsQuery) (*models.Project, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.Project
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"key": key, "workspace_id": workspaceId}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Project not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetByKeyAndWorkspaceId").Str("functionInline", "q.collection.FindOne").Msg("projectQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+func (q *projectQuery) TotalByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error) {
+	filter["workspace_id"] = workspaceId
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	total, err := q.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "TotalByWorkspaceId").Str("functionInline", "q.collection.CountDocuments").Msg("projectQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return total, nil
+}
+
+func (q *projectQuery) GetByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.Project, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	filter["workspace_id"] = workspaceId
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var projects []models.Project
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Limit:      opt.QueryPaginationLimit(),
+		Skip:       opt.QueryPaginationSkip(),
+		Sort:       opt.QuerySort(),
+	}
+	cursor, err := q.collection.Find(ctx, filter, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByWorkspaceId").Str("functionInline", "q.collection.Find").Msg("projectQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &projects); err != nil {
+		logger.Error().Err(err).Str("function", "GetByWorkspaceId").Str("functionInline", "cursor.All").Msg("projectQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return projects, nil
+}
+
+func (q *projectQuery) UpdateByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, data bson.M) (*models.Project, error) {
+	data["updated_at"] = time.Now()
+	setSearchFields(data, "name")
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var project models.Project
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "workspace_id": workspaceId}, bson.M{"$set": data}, optUpdate).Decode(&project); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Project not found"})
+		}
+		logger.Error().Err(err).Str("function", "UpdateByIdAndWorkspaceId").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("projectQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &project, nil
+}
+
+func (q *projectQuery) DeleteByIdAndWorkspaceId(id, workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.DeleteOne(ctx, bson.M{"_id": id, "workspace_id": workspaceId})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteByIdAndWorkspaceId").Str("functionInline", "q.collection.DeleteOne").Msg("projectQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.DeletedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Project not found"})
+	}
+	return nil
+}
+
+func (q *projectQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"workspace_id": workspaceId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceId").Str("functionInline", "q.collection.DeleteMany").Msg("projectQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *projectQuery) SearchText(search string, workspaceIds []primitive.ObjectID, limit int64) ([]TextScored[models.Project], error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var projects []TextScored[models.Project]
+	cursor, err := q.collection.Find(ctx, textSearchFilter(search, workspaceIds), textSearchOptions(limit, "name", "key", "description", "workspace_id"))
+	if err != nil {
+		logger.Error().Err(err).Str("function", "SearchText").Str("functionInline", "q.collection.Find").Msg("projectQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &projects); err != nil {
+		logger.Error().Err(err).Str("function", "SearchText").Str("functionInline", "cursor.All").Msg("projectQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return projects, nil
+}
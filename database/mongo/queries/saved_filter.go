@@ -0,0 +1,170 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type SavedFilterQuery interface {
+	Create(savedFilter models.SavedFilter) (newSavedFilter *models.SavedFilter, err error)
+	GetByIdAndUserIdAndWorkspaceId(id, userId, workspaceId primitive.ObjectID, opts ...OptionsQuery) (savedFilter *models.SavedFilter, err error)
+	TotalByUserIdAndWorkspaceId(userId, workspaceId primitive.ObjectID) (int64, error)
+	GetByUserIdAndWorkspaceId(userId, workspaceId primitive.ObjectID, opts ...OptionsQuery) ([]models.SavedFilter, error)
+	UpdateByIdAndUserIdAndWorkspaceId(id, userId, workspaceId primitive.ObjectID, data bson.M) (savedFilter *models.SavedFilter, err error)
+	DeleteByIdAndUserIdAndWorkspaceId(id, userId, workspaceId primitive.ObjectID) error
+	DeleteByUserIdAndWorkspaceId(userId, workspaceId primitive.ObjectID) error
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type savedFilterQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewSavedFilter(ctx context.Context) SavedFilterQuery {
+	return &savedFilterQuery{
+		collection: mongo.NewUtilityService().GetSavedFilterCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *savedFilterQuery) Create(data models.SavedFilter) (*models.SavedFilter, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Filter name already exists"})
+		}
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("savedFilterQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *savedFilterQuery) GetByIdAndUserIdAndWorkspaceId(id, userId, workspaceId primitive.ObjectID, opts ...OptionsQuery) (*models.SavedFilter, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.SavedFilter
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"_id": id, "user_id": userId, "workspace_id": workspaceId}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Filter not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetByIdAndUserIdAndWorkspaceId").Str("functionInline", "q.collection.FindOne").Msg("savedFilterQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+func (q *savedFilterQuery) TotalByUserIdAndWorkspaceId(userId, workspaceId primitive.ObjectID) (int64, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	total, err := q.collection.CountDocuments(ctx, bson.M{"user_id": userId, "workspace_id": workspaceId})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "TotalByUserIdAndWorkspaceId").Str("functionInline", "q.collection.CountDocuments").Msg("savedFilterQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return total, nil
+}
+
+func (q *savedFilterQuery) GetByUserIdAndWorkspaceId(userId, workspaceId primitive.ObjectID, opts ...OptionsQuery) ([]models.SavedFilter, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var savedFilters []models.SavedFilter
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Limit:      opt.QueryPaginationLimit(),
+		Skip:       opt.QueryPaginationSkip(),
+		Sort:       opt.QuerySort(),
+	}
+	cursor, err := q.collection.Find(ctx, bson.M{"user_id": userId, "workspace_id": workspaceId}, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByUserIdAndWorkspaceId").Str("functionInline", "q.collection.Find").Msg("savedFilterQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &savedFilters); err != nil {
+		logger.Error().Err(err).Str("function", "GetByUserIdAndWorkspaceId").Str("functionInline", "cursor.All").Msg("savedFilterQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return savedFilters, nil
+}
+
+func (q *savedFilterQuery) UpdateByIdAndUserIdAndWorkspaceId(id, userId, workspaceId primitive.ObjectID, data bson.M) (*models.SavedFilter, error) {
+	data["updated_at"] = time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var savedFilter models.SavedFilter
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	filter := bson.M{"_id": id, "user_id": userId, "workspace_id": workspaceId}
+	if err := q.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": data}, optUpdate).Decode(&savedFilter); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Filter not found"})
+		}
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Filter name already exists"})
+		}
+		logger.Error().Err(err).Str("function", "UpdateByIdAndUserIdAndWorkspaceId").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("savedFilterQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &savedFilter, nil
+}
+
+func (q *savedFilterQuery) DeleteByIdAndUserIdAndWorkspaceId(id, userId, workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userId, "workspace_id": workspaceId})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByIdAndUserIdAndWorkspaceId").Str("functionInline", "q.collection.DeleteOne").Msg("savedFilterQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.DeletedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Filter not found"})
+	}
+	return nil
+}
+
+func (q *savedFilterQuery) DeleteByUserIdAndWorkspaceId(userId, workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"user_id": userId, "workspace_id": workspaceId}); err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteByUserIdAndWorkspaceId").Str("functionInline", "q.collection.DeleteMany").Msg("savedFilterQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *savedFilterQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"workspace_id": workspaceId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceId").Str("functionInline", "q.collection.DeleteMany").Msg("savedFilterQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package queries
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TextScored pairs a document matched by a $text query with the relevance Mongo assigned to it.
+type TextScored[T any] struct {
+	Document T       `bson:",inline"`
+	Score    float64 `bson:"score"`
+}
+
+// textSearchFilter matches search against the text index of a collection, restricted to workspaceIds.
+func textSearchFilter(search string, workspaceIds []primitive.ObjectID) bson.M {
+	return bson.M{"$text": bson.M{"$search": search}, "workspace_id": bson.M{"$in": workspaceIds}}
+}
+
+// textSearchOptions returns the best limit matches first, with fields and their score.
+func textSearchOptions(limit int64, fields ...string) *options.FindOptions {
+	projection := bson.M{"score": bson.M{"$meta": "textScore"}}
+	for _, field := range fields {
+		projection[field] = 1
+	}
+	return options.Find().
+		SetProjection(projection).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: SortTypeDesc}}).
+		SetLimit(limit)
+}
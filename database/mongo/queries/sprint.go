@@ -0,0 +1,223 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type SprintQuery interface {
+	Create(sprint models.Sprint) (newSprint *models.Sprint, err error)
+	GetByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, opts ...OptionsQuery) (sprint *models.Sprint, err error)
+	TotalByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error)
+	GetByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.Sprint, error)
+	UpdateByIdAndWorkspaceIdAndState(id, workspaceId primitive.ObjectID, state string, data bson.M) (sprint *models.Sprint, err error)
+	TouchOpenByIdAndWorkspaceId(id, workspaceId primitive.ObjectID) error
+	DeleteByIdAndWorkspaceIdAndState(id, workspaceId primitive.ObjectID, state string) error
+	DeleteByProjectId(projectId primitive.ObjectID) error
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type sprintQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewSprint(ctx context.Context) SprintQuery {
+	return &sprintQuery{
+		collection: mongo.NewUtilityService().GetSprintCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *sprintQuery) Create(data models.Sprint) (*models.Sprint, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	data.SetSearchFields()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("sprintQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *sprintQuery) GetByIdAndWorkspaceId(id, workspaceId primitive.ObjectID, opts ...OptionsQuery) (*models.Sprint, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.Sprint
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"_id": id, "workspace_id": workspaceId}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Sprint not found"})
+		}
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "GetByIdAndWorkspaceId").Str("functionInline", "q.collection.FindOne").Msg("sprintQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+func (q *sprintQuery) TotalByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error) {
+	filter["workspace_id"] = workspaceId
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	total, err := q.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "TotalByWorkspaceId").Str("functionInline", "q.collection.CountDocuments").Msg("sprintQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return total, nil
+}
+
+func (q *sprintQuery) GetByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.Sprint, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	filter["workspace_id"] = workspaceId
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var sprints []models.Sprint
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Limit:      opt.QueryPaginationLimit(),
+		Skip:       opt.QueryPaginationSkip(),
+		Sort:       opt.QuerySort(),
+	}
+	cursor, err := q.collection.Find(ctx, filter, optFind)
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "GetByWorkspaceId").Str("functionInline", "q.collection.Find").Msg("sprintQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &sprints); err != nil {
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "GetByWorkspaceId").Str("functionInline", "cursor.All").Msg("sprintQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return sprints, nil
+}
+
+// UpdateByIdAndWorkspaceIdAndState only applies while the sprint is still in state, so state changes
+// such as start and complete cannot run twice.
+func (q *sprintQuery) UpdateByIdAndWorkspaceIdAndState(id, workspaceId primitive.ObjectID, state string, data bson.M) (*models.Sprint, error) {
+	data["updated_at"] = time.Now()
+	setSearchFields(data, "name")
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var sprint models.Sprint
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	filter := bson.M{"_id": id, "workspace_id": workspaceId, "state": state}
+	if err := q.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": data}, optUpdate).Decode(&sprint); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{
+				Data:       respErr.ErrSprintStateInvalid,
+				ReturnCode: constants.ReturnCodeSprintStateInvalid,
+			})
+		}
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{
+				Data:       respErr.ErrSprintActiveExists,
+				ReturnCode: constants.ReturnCodeSprintActiveExists,
+			})
+		}
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "UpdateByIdAndWorkspaceIdAndState").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("sprintQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &sprint, nil
+}
+
+// TouchOpenByIdAndWorkspaceId bumps updated_at of a sprint that is not closed. Writes that rely on the
+// sprint staying open do it in their transaction, so they conflict with completing the sprint.
+func (q *sprintQuery) TouchOpenByIdAndWorkspaceId(id, workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	filter := bson.M{"_id": id, "workspace_id": workspaceId, "state": bson.M{"$ne": constants.SprintStateClosed}}
+	result, err := q.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "TouchOpenByIdAndWorkspaceId").Str("functionInline", "q.collection.UpdateOne").Msg("sprintQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.MatchedCount == 0 {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{
+			Data:       respErr.ErrSprintStateInvalid,
+			ReturnCode: constants.ReturnCodeSprintStateInvalid,
+		})
+	}
+	return nil
+}
+
+func (q *sprintQuery) DeleteByIdAndWorkspaceIdAndState(id, workspaceId primitive.ObjectID, state string) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.DeleteOne(ctx, bson.M{"_id": id, "workspace_id": workspaceId, "state": state})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteByIdAndWorkspaceIdAndState").Str("functionInline", "q.collection.DeleteOne").Msg("sprintQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.DeletedCount == 0 {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{
+			Data:       respErr.ErrSprintStateInvalid,
+			ReturnCode: constants.ReturnCodeSprintStateInvalid,
+		})
+	}
+	return nil
+}
+
+func (q *sprintQuery) DeleteByProjectId(projectId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"project_id": projectId}); err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteByProjectId").Str("functionInline", "q.collection.DeleteMany").Msg("sprintQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *sprintQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"workspace_id": workspaceId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceId").Str("functionInline", "q.collection.DeleteMany").Msg("sprintQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
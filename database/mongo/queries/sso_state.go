@@ -0,0 +1,66 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type SsoStateQuery interface {
+	Create(ssoState models.SsoState) (newSsoState *models.SsoState, err error)
+	DeleteByStateAndProvider(state, provider string) (ssoState *models.SsoState, err error)
+}
+
+type ssoStateQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewSsoState(ctx context.Context) SsoStateQuery {
+	return &ssoStateQuery{
+		collection: mongo.NewUtilityService().GetSsoStateCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *ssoStateQuery) Create(data models.SsoState) (*models.SsoState, error) {
+	data.CreatedAt = time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("ssoStateQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+// DeleteByStateAndProvider consumes the state and returns it. Unknown, expired and already consumed
+// states are rejected alike.
+func (q *ssoStateQuery) DeleteByStateAndProvider(state, provider string) (*models.SsoState, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var ssoState models.SsoState
+	if err := q.collection.FindOneAndDelete(ctx, bson.M{
+		"state":      state,
+		"provider":   provider,
+		"expired_at": bson.M{"$gt": time.Now()},
+	}).Decode(&ssoState); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrSsoStateWrong})
+		}
+		logger.Error().Err(err).Str("function", "DeleteByStateAndProvider").Str("functionInline", "q.collection.FindOneAndDelete").Msg("ssoStateQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &ssoState, nil
+}
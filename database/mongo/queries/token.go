@@ -21,6 +21,11 @@ type TokenQuery interface {
 	GetById(id primitive.ObjectID, opts ...OptionsQuery) (webToken *models.Token, err error)
 	DeleteById(id primitive.ObjectID) error
 	DeleteByUserId(userId primitive.ObjectID) error
+	DeleteByFamilyId(familyId primitive.ObjectID) error
+	RevokeById(id primitive.ObjectID) (previous *models.Token, err error)
+	DeleteByIdAndUserId(id, userId primitive.ObjectID) error
+	GetActiveByUserId(userId primitive.ObjectID, opts ...OptionsQuery) (webTokens []models.Token, err error)
+	TouchById(id primitive.ObjectID, ipAddress string) error
 }
 
 type webTokenQuery struct {
@@ -64,6 +69,7 @@ func (q *webTokenQuery) Create(data models.Token) (id primitive.ObjectID, err er
 	currentTime := time.Now()
 	data.UpdatedAt = currentTime
 	data.CreatedAt = currentTime
+	data.LastUsedAt = currentTime
 	ctx, cancel := timeoutFunc(q.context)
 	defer cancel()
 	result, err := q.collection.InsertOne(ctx, data)
@@ -89,3 +95,87 @@ func (q *webTokenQuery) DeleteByUserId(userId primitive.ObjectID) error {
 	}
 	return nil
 }
+
+func (q *webTokenQuery) DeleteByFamilyId(familyId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"family_id": familyId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByFamilyId").Str("functionInline", "q.collection.DeleteMany").Msg("webTokenQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+// RevokeById marks the token as revoked and returns the document as it was before the update,
+// so the caller can tell whether it was already revoked (a replayed refresh token).
+func (q *webTokenQuery) RevokeById(id primitive.ObjectID) (*models.Token, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var previous models.Token
+	currentTime := time.Now()
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"revoked_at": currentTime, "updated_at": currentTime},
+	}, optUpdate).Decode(&previous); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusUnauthorized, response.ErrorOptions{Data: respErr.ErrTokenRevoked})
+		}
+		logger.Error().Err(err).Str("function", "RevokeById").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("webTokenQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &previous, nil
+}
+
+func (q *webTokenQuery) DeleteByIdAndUserId(id, userId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userId})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByIdAndUserId").Str("functionInline", "q.collection.DeleteOne").Msg("webTokenQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.DeletedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: respErr.ErrResourceNotFound})
+	}
+	return nil
+}
+
+func (q *webTokenQuery) GetActiveByUserId(userId primitive.ObjectID, opts ...OptionsQuery) ([]models.Token, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var webTokens []models.Token
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Sort:       opt.QuerySort(),
+	}
+	cursor, err := q.collection.Find(ctx, bson.M{
+		"user_id":    userId,
+		"revoked_at": nil,
+		"expired_at": bson.M{"$gt": time.Now()},
+	}, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetActiveByUserId").Str("functionInline", "q.collection.Find").Msg("webTokenQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &webTokens); err != nil {
+		logger.Error().Err(err).Str("function", "GetActiveByUserId").Str("functionInline", "cursor.All").Msg("webTokenQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return webTokens, nil
+}
+
+func (q *webTokenQuery) TouchById(id primitive.ObjectID, ipAddress string) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.UpdateByID(ctx, id, bson.M{
+		"$set": bson.M{"last_used_at": time.Now(), "ip_address": ipAddress},
+	}); err != nil {
+		logger.Error().Err(err).Str("function", "TouchById").Str("functionInline", "q.collection.UpdateByID").Msg("webTokenQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
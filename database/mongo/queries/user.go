@@ -15,10 +15,19 @@ import (
 	"jira-clone-api/database/mongo/models"
 )
 
+// emailCollation compares emails case-insensitively; the email index is built with the same collation.
+var emailCollation = &options.Collation{Locale: "en", Strength: 2}
+
 type UserQuery interface {
 	GetById(id primitive.ObjectID, opts ...OptionsQuery) (user *models.User, err error)
 	Create(user models.User) (newUser *models.User, err error)
 	GetByUsername(username string, opts ...OptionsQuery) (user *models.User, err error)
+	GetByIds(ids []primitive.ObjectID, opts ...OptionsQuery) (users []models.User, err error)
+	GetByEmail(email string, opts ...OptionsQuery) (users []models.User, err error)
+	UpdateById(id primitive.ObjectID, data bson.M) (user *models.User, err error)
+	UseMfaStepById(id primitive.ObjectID, step int64) (used bool, err error)
+	UseMfaRecoveryCodeById(id primitive.ObjectID, hash string) (used bool, err error)
+	DisableMfaById(id primitive.ObjectID) error
 }
 
 type userQuery struct {
@@ -63,6 +72,9 @@ func (q *userQuery) Create(data models.User) (user *models.User, err error) {
 		if mongoDriver.IsDuplicateKeyError(err) {
 			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "User already exists"})
 		}
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
 		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("userQuery")
 		return nil, response.NewError(fiber.StatusInternalServerError)
 	}
@@ -88,3 +100,107 @@ func (q *userQuery) GetByUsername(username string, opts ...OptionsQuery) (*model
 	}
 	return &data, nil
 }
+
+func (q *userQuery) GetByIds(ids []primitive.ObjectID, opts ...OptionsQuery) ([]models.User, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var users []models.User
+	optFind := &options.FindOptions{Projection: opt.QueryOnlyField()}
+	cursor, err := q.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByIds").Str("functionInline", "q.collection.Find").Msg("userQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &users); err != nil {
+		logger.Error().Err(err).Str("function", "GetByIds").Str("functionInline", "cursor.All").Msg("userQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return users, nil
+}
+
+// GetByEmail matches email case-insensitively. Emails are not unique, so several accounts may share one.
+func (q *userQuery) GetByEmail(email string, opts ...OptionsQuery) ([]models.User, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var users []models.User
+	optFind := &options.FindOptions{Projection: opt.QueryOnlyField(), Collation: emailCollation}
+	cursor, err := q.collection.Find(ctx, bson.M{"email": email}, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByEmail").Str("functionInline", "q.collection.Find").Msg("userQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &users); err != nil {
+		logger.Error().Err(err).Str("function", "GetByEmail").Str("functionInline", "cursor.All").Msg("userQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return users, nil
+}
+
+func (q *userQuery) UpdateById(id primitive.ObjectID, data bson.M) (*models.User, error) {
+	data["updated_at"] = time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var user models.User
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": data}, optUpdate).Decode(&user); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "User not found"})
+		}
+		logger.Error().Err(err).Str("function", "UpdateById").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("userQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &user, nil
+}
+
+// UseMfaStepById records that a TOTP code of period step was accepted. It reports false when a code
+// of this or a later period was already accepted, which makes each code single-use even when two
+// requests race.
+func (q *userQuery) UseMfaStepById(id primitive.ObjectID, step int64) (bool, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.UpdateOne(ctx, bson.M{
+		"_id":           id,
+		"mfa_last_step": bson.M{"$not": bson.M{"$gte": step}},
+	}, bson.M{"$set": bson.M{"mfa_last_step": step}})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "UseMfaStepById").Str("functionInline", "q.collection.UpdateOne").Msg("userQuery")
+		return false, response.NewError(fiber.StatusInternalServerError)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// UseMfaRecoveryCodeById removes the recovery code with the given hash and reports whether it was there.
+func (q *userQuery) UseMfaRecoveryCodeById(id primitive.ObjectID, hash string) (bool, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.UpdateOne(ctx, bson.M{
+		"_id":                id,
+		"mfa_recovery_codes": hash,
+	}, bson.M{"$pull": bson.M{"mfa_recovery_codes": hash}, "$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "UseMfaRecoveryCodeById").Str("functionInline", "q.collection.UpdateOne").Msg("userQuery")
+		return false, response.NewError(fiber.StatusInternalServerError)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (q *userQuery) DisableMfaById(id primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$unset": bson.M{"mfa_enabled_at": "", "mfa_secret": "", "mfa_recovery_codes": "", "mfa_last_step": ""},
+	}); err != nil {
+		logger.Error().Err(err).Str("function", "DisableMfaById").Str("functionInline", "q.collection.UpdateOne").Msg("userQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
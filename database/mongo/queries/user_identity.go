@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type UserIdentityQuery interface {
+	Create(userIdentity models.UserIdentity) (newUserIdentity *models.UserIdentity, err error)
+	GetByProviderAndSubject(provider, subject string) (userIdentity *models.UserIdentity, err error)
+	TouchById(id primitive.ObjectID) error
+}
+
+type userIdentityQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewUserIdentity(ctx context.Context) UserIdentityQuery {
+	return &userIdentityQuery{
+		collection: mongo.NewUtilityService().GetUserIdentityCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *userIdentityQuery) Create(data models.UserIdentity) (*models.UserIdentity, error) {
+	currentTime := time.Now()
+	data.CreatedAt = currentTime
+	data.LastLoginAt = currentTime
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "User identity already exists"})
+		}
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("userIdentityQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *userIdentityQuery) GetByProviderAndSubject(provider, subject string) (*models.UserIdentity, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var userIdentity models.UserIdentity
+	if err := q.collection.FindOne(ctx, bson.M{"provider": provider, "subject": subject}).Decode(&userIdentity); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "User identity not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetByProviderAndSubject").Str("functionInline", "q.collection.FindOne").Msg("userIdentityQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &userIdentity, nil
+}
+
+func (q *userIdentityQuery) TouchById(id primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_login_at": time.Now()}}); err != nil {
+		logger.Error().Err(err).Str("function", "TouchById").Str("functionInline", "q.collection.UpdateOne").Msg("userIdentityQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
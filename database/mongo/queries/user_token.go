@@ -0,0 +1,81 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type UserTokenQuery interface {
+	Create(userToken models.UserToken) (newUserToken *models.UserToken, err error)
+	UseByHashAndPurpose(hash, purpose string) (userToken *models.UserToken, err error)
+	DeleteByUserIdAndPurpose(userId primitive.ObjectID, purpose string) error
+}
+
+type userTokenQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewUserToken(ctx context.Context) UserTokenQuery {
+	return &userTokenQuery{
+		collection: mongo.NewUtilityService().GetUserTokenCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *userTokenQuery) Create(data models.UserToken) (*models.UserToken, error) {
+	data.CreatedAt = time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("userTokenQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+// UseByHashAndPurpose marks the token as used and returns it. Unknown, expired and already used
+// tokens are rejected alike, and two concurrent requests can never both use the same token.
+func (q *userTokenQuery) UseByHashAndPurpose(hash, purpose string) (*models.UserToken, error) {
+	currentTime := time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var userToken models.UserToken
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{
+		"hash":       hash,
+		"purpose":    purpose,
+		"used_at":    nil,
+		"expired_at": bson.M{"$gt": currentTime},
+	}, bson.M{"$set": bson.M{"used_at": currentTime}}, optUpdate).Decode(&userToken); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusBadRequest, response.ErrorOptions{Data: respErr.ErrTokenWrong})
+		}
+		logger.Error().Err(err).Str("function", "UseByHashAndPurpose").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("userTokenQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &userToken, nil
+}
+
+func (q *userTokenQuery) DeleteByUserIdAndPurpose(userId primitive.ObjectID, purpose string) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"user_id": userId, "purpose": purpose}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByUserIdAndPurpose").Str("functionInline", "q.collection.DeleteMany").Msg("userTokenQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
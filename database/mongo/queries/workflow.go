@@ -0,0 +1,120 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/constants"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type WorkflowQuery interface {
+	Create(workflow models.Workflow) (newWorkflow *models.Workflow, err error)
+	GetByWorkspaceId(workspaceId primitive.ObjectID, opts ...OptionsQuery) (workflow *models.Workflow, err error)
+	UpdateByWorkspaceId(workspaceId primitive.ObjectID, data bson.M) (workflow *models.Workflow, err error)
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type workflowQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+// WorkflowError turns a refusal of models.Workflow.CanTransition into its response.
+func WorkflowError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrWorkflowStatusUnknown):
+		return response.NewError(fiber.StatusBadRequest, response.ErrorOptions{
+			Data:       respErr.ErrIssueStatusNotInWorkflow,
+			ReturnCode: constants.ReturnCodeIssueStatusNotInWorkflow,
+		})
+	case errors.Is(err, models.ErrWorkflowTransitionRefused):
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{
+			Data:       respErr.ErrIssueTransitionNotAllowed,
+			ReturnCode: constants.ReturnCodeIssueTransitionNotAllowed,
+		})
+	}
+	return err
+}
+
+func NewWorkflow(ctx context.Context) WorkflowQuery {
+	return &workflowQuery{
+		collection: mongo.NewUtilityService().GetWorkflowCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *workflowQuery) Create(data models.Workflow) (*models.Workflow, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Workspace already has a workflow"})
+		}
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("workflowQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *workflowQuery) GetByWorkspaceId(workspaceId primitive.ObjectID, opts ...OptionsQuery) (*models.Workflow, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.Workflow
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"workspace_id": workspaceId}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workflow not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetByWorkspaceId").Str("functionInline", "q.collection.FindOne").Msg("workflowQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+func (q *workflowQuery) UpdateByWorkspaceId(workspaceId primitive.ObjectID, data bson.M) (*models.Workflow, error) {
+	data["updated_at"] = time.Now()
+	var workflow models.Workflow
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{"workspace_id": workspaceId}, bson.M{"$set": data}, optUpdate).Decode(&workflow); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workflow not found"})
+		}
+		logger.Error().Err(err).Str("function", "UpdateByWorkspaceId").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("workflowQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &workflow, nil
+}
+
+func (q *workflowQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteOne(ctx, bson.M{"workspace_id": workspaceId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceId").Str("functionInline", "q.collection.DeleteOne").Msg("workflowQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
@@ -3,7 +3,6 @@ package queries
 import (
 	"context"
 	"errors"
-	"regexp"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -18,9 +17,18 @@ import (
 
 type WorkspaceQuery interface {
 	GetById(id primitive.ObjectID, opts ...OptionsQuery) (workspace *models.Workspace, err error)
+	GetByIds(ids []primitive.ObjectID, opts ...OptionsQuery) ([]models.Workspace, error)
 	Create(workspace models.Workspace) (newWorkspace *models.Workspace, err error)
-	TotalByNameRegexAndUserId(name string, userId primitive.ObjectID) (int64, error)
-	GetByNameRegexAndUserId(name string, userId primitive.ObjectID, opts ...OptionsQuery) ([]models.Workspace, error)
+	TotalByIdsAndFilter(ids []primitive.ObjectID, filter bson.M) (int64, error)
+	GetByIdsAndFilter(ids []primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.Workspace, error)
+	SearchText(search string, workspaceIds []primitive.ObjectID, limit int64) ([]TextScored[models.Workspace], error)
+	UpdateById(id primitive.ObjectID, data bson.M) (workspace *models.Workspace, err error)
+	TouchById(id primitive.ObjectID) error
+	SoftDeleteById(id primitive.ObjectID) (deletedAt time.Time, err error)
+	GetDeletedById(id primitive.ObjectID, opts ...OptionsQuery) (workspace *models.Workspace, err error)
+	RestoreById(id primitive.ObjectID, deletedAfter time.Time) error
+	GetDeletedBefore(deletedBefore time.Time, opts ...OptionsQuery) ([]models.Workspace, error)
+	DeleteById(id primitive.ObjectID) error
 }
 
 type workspaceQuery struct {
@@ -44,7 +52,7 @@ func (q *workspaceQuery) GetById(id primitive.ObjectID, opts ...OptionsQuery) (*
 	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
 	ctx, cancel := timeoutFunc(q.context)
 	defer cancel()
-	if err := q.collection.FindOne(ctx, bson.M{"_id": id}, optFind).Decode(&data); err != nil {
+	if err := q.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}, optFind).Decode(&data); err != nil {
 		if errors.Is(err, mongoDriver.ErrNoDocuments) {
 			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace not found"})
 		}
@@ -54,10 +62,32 @@ func (q *workspaceQuery) GetById(id primitive.ObjectID, opts ...OptionsQuery) (*
 	return &data, nil
 }
 
+func (q *workspaceQuery) GetByIds(ids []primitive.ObjectID, opts ...OptionsQuery) ([]models.Workspace, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var workspaces []models.Workspace
+	optFind := &options.FindOptions{Projection: opt.QueryOnlyField()}
+	cursor, err := q.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil}, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByIds").Str("functionInline", "q.collection.Find").Msg("workspaceQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &workspaces); err != nil {
+		logger.Error().Err(err).Str("function", "GetByIds").Str("functionInline", "cursor.All").Msg("workspaceQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return workspaces, nil
+}
+
 func (q *workspaceQuery) Create(data models.Workspace) (workspace *models.Workspace, err error) {
 	currentTime := time.Now()
 	data.UpdatedAt = currentTime
 	data.CreatedAt = currentTime
+	data.SetSearchFields()
 	ctx, cancel := timeoutFunc(q.context)
 	defer cancel()
 	result, err := q.collection.InsertOne(ctx, data)
@@ -65,6 +95,9 @@ func (q *workspaceQuery) Create(data models.Workspace) (workspace *models.Worksp
 		if mongoDriver.IsDuplicateKeyError(err) {
 			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Workspace already exists"})
 		}
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
 		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("workspaceQuery")
 		return nil, response.NewError(fiber.StatusInternalServerError)
 	}
@@ -72,22 +105,28 @@ func (q *workspaceQuery) Create(data models.Workspace) (workspace *models.Worksp
 	return &data, nil
 }
 
-func (q *workspaceQuery) TotalByNameRegexAndUserId(name string, userId primitive.ObjectID) (int64, error) {
+// TotalByIdsAndFilter counts the live workspaces among ids that match filter.
+func (q *workspaceQuery) TotalByIdsAndFilter(ids []primitive.ObjectID, filter bson.M) (int64, error) {
+	filter["_id"] = bson.M{"$in": ids}
+	filter["deleted_at"] = nil
 	ctx, cancel := timeoutFunc(q.context)
 	defer cancel()
-	total, err := q.collection.CountDocuments(ctx, bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(name), Options: "i"}}, "user_id": userId})
+	total, err := q.collection.CountDocuments(ctx, filter)
 	if err != nil {
-		logger.Error().Err(err).Str("function", "TotalByNameRegexAndUserId").Str("functionInline", "q.collection.FinCountDocumentsd").Msg("workspaceQuery")
+		logger.Error().Err(err).Str("function", "TotalByIdsAndFilter").Str("functionInline", "q.collection.CountDocuments").Msg("workspaceQuery")
 		return 0, response.NewError(fiber.StatusInternalServerError)
 	}
 	return total, nil
 }
 
-func (q *workspaceQuery) GetByNameRegexAndUserId(name string, userId primitive.ObjectID, opts ...OptionsQuery) ([]models.Workspace, error) {
+// GetByIdsAndFilter returns the live workspaces among ids that match filter.
+func (q *workspaceQuery) GetByIdsAndFilter(ids []primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.Workspace, error) {
 	opt := NewOptions()
 	if len(opts) > 0 {
 		opt = opts[0]
 	}
+	filter["_id"] = bson.M{"$in": ids}
+	filter["deleted_at"] = nil
 	ctx, cancel := timeoutFunc(q.context)
 	defer cancel()
 	var workspaces []models.Workspace
@@ -97,13 +136,158 @@ func (q *workspaceQuery) GetByNameRegexAndUserId(name string, userId primitive.O
 		Skip:       opt.QueryPaginationSkip(),
 		Sort:       opt.QuerySort(),
 	}
-	cursor, err := q.collection.Find(ctx, bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(name), Options: "i"}}, "user_id": userId}, optFind)
+	cursor, err := q.collection.Find(ctx, filter, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetByIdsAndFilter").Str("functionInline", "q.collection.Find").Msg("workspaceQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &workspaces); err != nil {
+		logger.Error().Err(err).Str("function", "GetByIdsAndFilter").Str("functionInline", "cursor.All").Msg("workspaceQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return workspaces, nil
+}
+
+func (q *workspaceQuery) UpdateById(id primitive.ObjectID, data bson.M) (*models.Workspace, error) {
+	data["updated_at"] = time.Now()
+	setSearchFields(data, "name")
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var workspace models.Workspace
+	optUpdate := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := q.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "deleted_at": nil}, bson.M{"$set": data}, optUpdate).Decode(&workspace); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace not found"})
+		}
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "Workspace already exists"})
+		}
+		logger.Error().Err(err).Str("function", "UpdateById").Str("functionInline", "q.collection.FindOneAndUpdate").Msg("workspaceQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &workspace, nil
+}
+
+// TouchById bumps updated_at. Transactions that check the members against each other write the
+// workspace first, so two of them conflict instead of both committing on the same snapshot.
+func (q *workspaceQuery) TouchById(id primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, bson.M{
+		"$set": bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "TouchById").Str("functionInline", "q.collection.UpdateOne").Msg("workspaceQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.MatchedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace not found"})
+	}
+	return nil
+}
+
+func (q *workspaceQuery) SoftDeleteById(id primitive.ObjectID) (time.Time, error) {
+	currentTime := time.Now()
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, bson.M{
+		"$set": bson.M{"deleted_at": currentTime, "updated_at": currentTime},
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "SoftDeleteById").Str("functionInline", "q.collection.UpdateOne").Msg("workspaceQuery")
+		return currentTime, response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.MatchedCount == 0 {
+		return currentTime, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace not found"})
+	}
+	return currentTime, nil
+}
+
+func (q *workspaceQuery) GetDeletedById(id primitive.ObjectID, opts ...OptionsQuery) (*models.Workspace, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.Workspace
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetDeletedById").Str("functionInline", "q.collection.FindOne").Msg("workspaceQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+// RestoreById undoes a soft delete made after deletedAfter, older deletions are past their restore window.
+func (q *workspaceQuery) RestoreById(id primitive.ObjectID, deletedAfter time.Time) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$gt": deletedAfter}}, bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "RestoreById").Str("functionInline", "q.collection.UpdateOne").Msg("workspaceQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.MatchedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace not found"})
+	}
+	return nil
+}
+
+func (q *workspaceQuery) GetDeletedBefore(deletedBefore time.Time, opts ...OptionsQuery) ([]models.Workspace, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var workspaces []models.Workspace
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Limit:      opt.QueryPaginationLimit(),
+	}
+	cursor, err := q.collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lte": deletedBefore}}, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetDeletedBefore").Str("functionInline", "q.collection.Find").Msg("workspaceQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &workspaces); err != nil {
+		logger.Error().Err(err).Str("function", "GetDeletedBefore").Str("functionInline", "cursor.All").Msg("workspaceQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return workspaces, nil
+}
+
+func (q *workspaceQuery) DeleteById(id primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteById").Str("functionInline", "q.collection.DeleteOne").Msg("workspaceQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *workspaceQuery) SearchText(search string, workspaceIds []primitive.ObjectID, limit int64) ([]TextScored[models.Workspace], error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var workspaces []TextScored[models.Workspace]
+	cursor, err := q.collection.Find(ctx, bson.M{"$text": bson.M{"$search": search}, "_id": bson.M{"$in": workspaceIds}, "deleted_at": nil}, textSearchOptions(limit, "name"))
 	if err != nil {
-		logger.Error().Err(err).Str("function", "GetByNameRegexAndUserId").Str("functionInline", "q.collection.Find").Msg("workspaceQuery")
+		logger.Error().Err(err).Str("function", "SearchText").Str("functionInline", "q.collection.Find").Msg("workspaceQuery")
 		return nil, response.NewError(fiber.StatusInternalServerError)
 	}
 	if err = cursor.All(ctx, &workspaces); err != nil {
-		logger.Error().Err(err).Str("function", "GetByNameRegexAndUserId").Str("functionInline", "cursor.All").Msg("workspaceQuery")
+		logger.Error().Err(err).Str("function", "SearchText").Str("functionInline", "cursor.All").Msg("workspaceQuery")
 		return nil, response.NewError(fiber.StatusInternalServerError)
 	}
 	return workspaces, nil
@@ -0,0 +1,184 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	respErr "jira-clone-api/common/response/error"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type WorkspaceInvitationQuery interface {
+	Create(invitation models.WorkspaceInvitation) (newInvitation *models.WorkspaceInvitation, err error)
+	GetById(id primitive.ObjectID, opts ...OptionsQuery) (invitation *models.WorkspaceInvitation, err error)
+	TotalPendingByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error)
+	GetPendingByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.WorkspaceInvitation, error)
+	RevokeByIdAndWorkspaceId(id, workspaceId primitive.ObjectID) error
+	MarkAcceptedById(id, userId primitive.ObjectID) error
+	RevokeByWorkspaceId(workspaceId primitive.ObjectID) error
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type workspaceInvitationQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewWorkspaceInvitation(ctx context.Context) WorkspaceInvitationQuery {
+	return &workspaceInvitationQuery{
+		collection: mongo.NewUtilityService().GetWorkspaceInvitationCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *workspaceInvitationQuery) Create(data models.WorkspaceInvitation) (*models.WorkspaceInvitation, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("workspaceInvitationQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *workspaceInvitationQuery) GetById(id primitive.ObjectID, opts ...OptionsQuery) (*models.WorkspaceInvitation, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.WorkspaceInvitation
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"_id": id}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Invitation not found"})
+		}
+		logger.Error().Err(err).Str("function", "GetById").Str("functionInline", "q.collection.FindOne").Msg("workspaceInvitationQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+// pendingFilter narrows filter to the invitations of the workspace that can still be accepted.
+func (q *workspaceInvitationQuery) pendingFilter(workspaceId primitive.ObjectID, filter bson.M) bson.M {
+	filter["workspace_id"] = workspaceId
+	filter["revoked_at"] = nil
+	filter["expired_at"] = bson.M{"$gt": time.Now()}
+	filter["$or"] = []bson.M{
+		{"email": ""},
+		{"accepted_at": nil},
+	}
+	return filter
+}
+
+func (q *workspaceInvitationQuery) TotalPendingByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	total, err := q.collection.CountDocuments(ctx, q.pendingFilter(workspaceId, filter))
+	if err != nil {
+		logger.Error().Err(err).Str("function", "TotalPendingByWorkspaceId").Str("functionInline", "q.collection.CountDocuments").Msg("workspaceInvitationQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return total, nil
+}
+
+func (q *workspaceInvitationQuery) GetPendingByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.WorkspaceInvitation, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var invitations []models.WorkspaceInvitation
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Limit:      opt.QueryPaginationLimit(),
+		Skip:       opt.QueryPaginationSkip(),
+		Sort:       opt.QuerySort(),
+	}
+	cursor, err := q.collection.Find(ctx, q.pendingFilter(workspaceId, filter), optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "GetPendingByWorkspaceId").Str("functionInline", "q.collection.Find").Msg("workspaceInvitationQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &invitations); err != nil {
+		logger.Error().Err(err).Str("function", "GetPendingByWorkspaceId").Str("functionInline", "cursor.All").Msg("workspaceInvitationQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return invitations, nil
+}
+
+func (q *workspaceInvitationQuery) RevokeByIdAndWorkspaceId(id, workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	currentTime := time.Now()
+	result, err := q.collection.UpdateOne(ctx, bson.M{"_id": id, "workspace_id": workspaceId, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"revoked_at": currentTime, "updated_at": currentTime},
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("function", "RevokeByIdAndWorkspaceId").Str("functionInline", "q.collection.UpdateOne").Msg("workspaceInvitationQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.MatchedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Invitation not found"})
+	}
+	return nil
+}
+
+// MarkAcceptedById consumes an email invitation, failing if someone accepted it first.
+func (q *workspaceInvitationQuery) MarkAcceptedById(id, userId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	currentTime := time.Now()
+	result, err := q.collection.UpdateOne(ctx, bson.M{"_id": id, "accepted_at": nil}, bson.M{
+		"$set": bson.M{"accepted_at": currentTime, "accepted_by": userId, "updated_at": currentTime},
+	})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "MarkAcceptedById").Str("functionInline", "q.collection.UpdateOne").Msg("workspaceInvitationQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.MatchedCount == 0 {
+		return response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: respErr.ErrTransactionUsed})
+	}
+	return nil
+}
+
+func (q *workspaceInvitationQuery) RevokeByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	currentTime := time.Now()
+	if _, err := q.collection.UpdateMany(ctx, bson.M{"workspace_id": workspaceId, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"revoked_at": currentTime, "updated_at": currentTime},
+	}); err != nil {
+		logger.Error().Err(err).Str("function", "RevokeByWorkspaceId").Str("functionInline", "q.collection.UpdateMany").Msg("workspaceInvitationQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (q *workspaceInvitationQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"workspace_id": workspaceId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceId").Str("functionInline", "q.collection.DeleteMany").Msg("workspaceInvitationQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
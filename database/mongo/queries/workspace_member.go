@@ -0,0 +1,202 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jira-clone-api/common/response"
+	"jira-clone-api/database/mongo"
+	"jira-clone-api/database/mongo/models"
+)
+
+type WorkspaceMemberQuery interface {
+	Create(member models.WorkspaceMember) (newMember *models.WorkspaceMember, err error)
+	GetByWorkspaceIdAndUserId(workspaceId, userId primitive.ObjectID, opts ...OptionsQuery) (member *models.WorkspaceMember, err error)
+	IsMember(workspaceId, userId primitive.ObjectID) (bool, error)
+	TotalByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error)
+	GetByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.WorkspaceMember, error)
+	GetByUserId(userId primitive.ObjectID, opts ...OptionsQuery) ([]models.WorkspaceMember, error)
+	TotalByWorkspaceIdAndRole(workspaceId primitive.ObjectID, role string) (int64, error)
+	UpdateRoleByWorkspaceIdAndUserId(workspaceId, userId primitive.ObjectID, role string) error
+	DeleteByWorkspaceIdAndUserId(workspaceId, userId primitive.ObjectID) error
+	DeleteByWorkspaceId(workspaceId primitive.ObjectID) error
+}
+
+type workspaceMemberQuery struct {
+	collection *mongoDriver.Collection
+	context    context.Context
+}
+
+func NewWorkspaceMember(ctx context.Context) WorkspaceMemberQuery {
+	return &workspaceMemberQuery{
+		collection: mongo.NewUtilityService().GetWorkspaceMemberCollection(),
+		context:    ctx,
+	}
+}
+
+func (q *workspaceMemberQuery) Create(data models.WorkspaceMember) (*models.WorkspaceMember, error) {
+	currentTime := time.Now()
+	data.UpdatedAt = currentTime
+	data.CreatedAt = currentTime
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.InsertOne(ctx, data)
+	if err != nil {
+		if mongoDriver.IsDuplicateKeyError(err) {
+			return nil, response.NewError(fiber.StatusConflict, response.ErrorOptions{Data: "User is already a member of this workspace"})
+		}
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "Create").Str("functionInline", "q.collection.InsertOne").Msg("workspaceMemberQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	data.Id = result.InsertedID.(primitive.ObjectID)
+	return &data, nil
+}
+
+func (q *workspaceMemberQuery) GetByWorkspaceIdAndUserId(workspaceId, userId primitive.ObjectID, opts ...OptionsQuery) (*models.WorkspaceMember, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var data models.WorkspaceMember
+	optFind := &options.FindOneOptions{Projection: opt.QueryOnlyField()}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if err := q.collection.FindOne(ctx, bson.M{"workspace_id": workspaceId, "user_id": userId}, optFind).Decode(&data); err != nil {
+		if errors.Is(err, mongoDriver.ErrNoDocuments) {
+			return nil, response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace member not found"})
+		}
+		if isTransientTransactionError(err) {
+			return nil, err
+		}
+		logger.Error().Err(err).Str("function", "GetByWorkspaceIdAndUserId").Str("functionInline", "q.collection.FindOne").Msg("workspaceMemberQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return &data, nil
+}
+
+func (q *workspaceMemberQuery) IsMember(workspaceId, userId primitive.ObjectID) (bool, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	total, err := q.collection.CountDocuments(ctx, bson.M{"workspace_id": workspaceId, "user_id": userId}, options.Count().SetLimit(1))
+	if err != nil {
+		logger.Error().Err(err).Str("function", "IsMember").Str("functionInline", "q.collection.CountDocuments").Msg("workspaceMemberQuery")
+		return false, response.NewError(fiber.StatusInternalServerError)
+	}
+	return total > 0, nil
+}
+
+func (q *workspaceMemberQuery) TotalByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M) (int64, error) {
+	filter["workspace_id"] = workspaceId
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	total, err := q.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error().Err(err).Str("function", "TotalByWorkspaceId").Str("functionInline", "q.collection.CountDocuments").Msg("workspaceMemberQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return total, nil
+}
+
+func (q *workspaceMemberQuery) GetByWorkspaceId(workspaceId primitive.ObjectID, filter bson.M, opts ...OptionsQuery) ([]models.WorkspaceMember, error) {
+	filter["workspace_id"] = workspaceId
+	return q.find(filter, "GetByWorkspaceId", opts...)
+}
+
+func (q *workspaceMemberQuery) GetByUserId(userId primitive.ObjectID, opts ...OptionsQuery) ([]models.WorkspaceMember, error) {
+	return q.find(bson.M{"user_id": userId}, "GetByUserId", opts...)
+}
+
+func (q *workspaceMemberQuery) find(filter bson.M, function string, opts ...OptionsQuery) ([]models.WorkspaceMember, error) {
+	opt := NewOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	var members []models.WorkspaceMember
+	optFind := &options.FindOptions{
+		Projection: opt.QueryOnlyField(),
+		Limit:      opt.QueryPaginationLimit(),
+		Skip:       opt.QueryPaginationSkip(),
+		Sort:       opt.QuerySort(),
+	}
+	cursor, err := q.collection.Find(ctx, filter, optFind)
+	if err != nil {
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "q.collection.Find").Msg("workspaceMemberQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	if err = cursor.All(ctx, &members); err != nil {
+		logger.Error().Err(err).Str("function", function).Str("functionInline", "cursor.All").Msg("workspaceMemberQuery")
+		return nil, response.NewError(fiber.StatusInternalServerError)
+	}
+	return members, nil
+}
+
+func (q *workspaceMemberQuery) TotalByWorkspaceIdAndRole(workspaceId primitive.ObjectID, role string) (int64, error) {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	total, err := q.collection.CountDocuments(ctx, bson.M{"workspace_id": workspaceId, "role": role})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return 0, err
+		}
+		logger.Error().Err(err).Str("function", "TotalByWorkspaceIdAndRole").Str("functionInline", "q.collection.CountDocuments").Msg("workspaceMemberQuery")
+		return 0, response.NewError(fiber.StatusInternalServerError)
+	}
+	return total, nil
+}
+
+func (q *workspaceMemberQuery) UpdateRoleByWorkspaceIdAndUserId(workspaceId, userId primitive.ObjectID, role string) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.UpdateOne(ctx, bson.M{"workspace_id": workspaceId, "user_id": userId}, bson.M{
+		"$set": bson.M{"role": role, "updated_at": time.Now()},
+	})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "UpdateRoleByWorkspaceIdAndUserId").Str("functionInline", "q.collection.UpdateOne").Msg("workspaceMemberQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.MatchedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace member not found"})
+	}
+	return nil
+}
+
+func (q *workspaceMemberQuery) DeleteByWorkspaceIdAndUserId(workspaceId, userId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	result, err := q.collection.DeleteOne(ctx, bson.M{"workspace_id": workspaceId, "user_id": userId})
+	if err != nil {
+		if isTransientTransactionError(err) {
+			return err
+		}
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceIdAndUserId").Str("functionInline", "q.collection.DeleteOne").Msg("workspaceMemberQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	if result.DeletedCount == 0 {
+		return response.NewError(fiber.StatusNotFound, response.ErrorOptions{Data: "Workspace member not found"})
+	}
+	return nil
+}
+
+func (q *workspaceMemberQuery) DeleteByWorkspaceId(workspaceId primitive.ObjectID) error {
+	ctx, cancel := timeoutFunc(q.context)
+	defer cancel()
+	if _, err := q.collection.DeleteMany(ctx, bson.M{"workspace_id": workspaceId}); err != nil {
+		logger.Error().Err(err).Str("function", "DeleteByWorkspaceId").Str("functionInline", "q.collection.DeleteMany").Msg("workspaceMemberQuery")
+		return response.NewError(fiber.StatusInternalServerError)
+	}
+	return nil
+}
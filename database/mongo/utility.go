@@ -10,9 +10,25 @@ import (
 
 type UtilityService interface {
 	GetContextTimeout(ctx context.Context) (context.Context, context.CancelFunc)
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 	GetUserCollection() (coll *mongo.Collection)
 	GetTokenCollection() (coll *mongo.Collection)
 	GetWorkspaceCollection() (coll *mongo.Collection)
+	GetWorkspaceMemberCollection() (coll *mongo.Collection)
+	GetMigrationCollection() (coll *mongo.Collection)
+	GetWorkspaceInvitationCollection() (coll *mongo.Collection)
+	GetProjectCollection() (coll *mongo.Collection)
+	GetCounterCollection() (coll *mongo.Collection)
+	GetIssueCollection() (coll *mongo.Collection)
+	GetWorkflowCollection() (coll *mongo.Collection)
+	GetSprintCollection() (coll *mongo.Collection)
+	GetCommentCollection() (coll *mongo.Collection)
+	GetAttachmentCollection() (coll *mongo.Collection)
+	GetSavedFilterCollection() (coll *mongo.Collection)
+	GetUserTokenCollection() (coll *mongo.Collection)
+	GetLoginAttemptCollection() (coll *mongo.Collection)
+	GetSsoStateCollection() (coll *mongo.Collection)
+	GetUserIdentityCollection() (coll *mongo.Collection)
 }
 
 type utilityService struct{}
@@ -26,6 +42,20 @@ func (s *utilityService) GetContextTimeout(ctx context.Context) (context.Context
 	return context.WithTimeout(ctx, cfg.MongoDBRequestTimeout)
 }
 
+// WithTransaction runs fn inside a transaction on the jira database. Collections used with the context
+// given to fn take part in the transaction, and the whole fn is retried on transient transaction errors.
+func (s *utilityService) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := jiraDBClient.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
 func (s *utilityService) getJiraDB() (db *mongo.Database) {
 	return jiraDBClient.Database(cfg.MongoDBJiraName)
 }
@@ -41,3 +71,63 @@ func (s *utilityService) GetTokenCollection() (coll *mongo.Collection) {
 func (s *utilityService) GetWorkspaceCollection() (coll *mongo.Collection) {
 	return s.getJiraDB().Collection(new(mongoModels.Workspace).CollectionName())
 }
+
+func (s *utilityService) GetWorkspaceMemberCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.WorkspaceMember).CollectionName())
+}
+
+func (s *utilityService) GetMigrationCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.Migration).CollectionName())
+}
+
+func (s *utilityService) GetWorkspaceInvitationCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.WorkspaceInvitation).CollectionName())
+}
+
+func (s *utilityService) GetProjectCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.Project).CollectionName())
+}
+
+func (s *utilityService) GetCounterCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.Counter).CollectionName())
+}
+
+func (s *utilityService) GetIssueCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.Issue).CollectionName())
+}
+
+func (s *utilityService) GetWorkflowCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.Workflow).CollectionName())
+}
+
+func (s *utilityService) GetSprintCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.Sprint).CollectionName())
+}
+
+func (s *utilityService) GetCommentCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.Comment).CollectionName())
+}
+
+func (s *utilityService) GetAttachmentCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.Attachment).CollectionName())
+}
+
+func (s *utilityService) GetSavedFilterCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.SavedFilter).CollectionName())
+}
+
+func (s *utilityService) GetUserTokenCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.UserToken).CollectionName())
+}
+
+func (s *utilityService) GetLoginAttemptCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.LoginAttempt).CollectionName())
+}
+
+func (s *utilityService) GetSsoStateCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.SsoState).CollectionName())
+}
+
+func (s *utilityService) GetUserIdentityCollection() (coll *mongo.Collection) {
+	return s.getJiraDB().Collection(new(mongoModels.UserIdentity).CollectionName())
+}
@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"jira-clone-api/common/request"
+	"jira-clone-api/database/mongo/models"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/storage"
+)
+
+const attachmentCleanupBatchSize = 100
+
+// StartAttachmentCleanup periodically removes attachments that were never confirmed or whose
+// issue was deleted, together with their objects. It stops when ctx is cancelled.
+func StartAttachmentCleanup(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(cfg.AttachmentCleanupInterval)
+		defer ticker.Stop()
+		for {
+			cleanupExpiredAttachments(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func cleanupExpiredAttachments(ctx context.Context) {
+	queryOption := queries.NewOptions()
+	queryOption.SetOnlyFields("_id", "object_key")
+	queryOption.SetPagination(&request.Pagination{Limit: attachmentCleanupBatchSize})
+	attachments, err := queries.NewAttachment(ctx).GetExpiredBefore(time.Now(), queryOption)
+	if err != nil {
+		return
+	}
+	for _, attachment := range attachments {
+		if err = deleteAttachmentObjects(attachment); err != nil {
+			logger.Error().Err(err).Str("function", "cleanupExpiredAttachments").Str("functionInline", "deleteAttachmentObjects").Msg("attachmentJob")
+			continue
+		}
+		if err = queries.NewAttachment(ctx).DeleteById(attachment.Id); err != nil {
+			continue
+		}
+	}
+}
+
+func deleteAttachmentObjects(attachment models.Attachment) error {
+	for _, key := range attachment.ObjectKeys() {
+		if err := storage.GetGlobal().DeleteObject(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/lexorank"
+)
+
+var rebalancingColumns sync.Map
+
+const boardRebalanceAttempts = 3
+
+// RebalanceBoardColumn respreads the ranks of a board column in the background once they have
+// grown too long. Requests for a column that is already being rebalanced are dropped.
+func RebalanceBoardColumn(workspaceId primitive.ObjectID, status string) {
+	key := workspaceId.Hex() + "/" + status
+	if _, running := rebalancingColumns.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	go func() {
+		defer rebalancingColumns.Delete(key)
+		rebalanceBoardColumn(context.Background(), workspaceId, status)
+	}()
+}
+
+// rebalanceBoardColumn gives the column evenly spread ranks in its current order. A card moved while
+// the ranks are written keeps its new rank and the column is read and spread again, up to
+// boardRebalanceAttempts times.
+func rebalanceBoardColumn(ctx context.Context, workspaceId primitive.ObjectID, status string) {
+	for attempt := 1; attempt <= boardRebalanceAttempts; attempt++ {
+		queryOption := queries.NewOptions()
+		queryOption.SetOnlyFields("_id", "status", "rank")
+		queryOption.AddSortKey(map[string]int{"rank": queries.SortTypeAsc})
+		queryOption.AddSortKey(map[string]int{"_id": queries.SortTypeAsc})
+		issues, err := queries.NewIssue(ctx).GetByWorkspaceId(workspaceId, bson.M{"status": status}, queryOption)
+		if err != nil {
+			return
+		}
+		matched, err := queries.NewIssue(ctx).UpdateRanks(issues, lexorank.New().Spread(len(issues)))
+		if err != nil {
+			return
+		}
+		if matched == int64(len(issues)) {
+			logger.Info().Str("workspaceId", workspaceId.Hex()).Str("status", status).Int("issues", len(issues)).Msg("board column rebalanced")
+			return
+		}
+	}
+	logger.Warn().Str("workspaceId", workspaceId.Hex()).Str("status", status).Msg("board column kept changing while being rebalanced")
+}
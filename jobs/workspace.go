@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"jira-clone-api/common/configure"
+	"jira-clone-api/common/logging"
+	"jira-clone-api/common/request"
+	"jira-clone-api/database/mongo/queries"
+	"jira-clone-api/utilities/storage"
+)
+
+var (
+	cfg    = configure.GetConfig()
+	logger = logging.GetLogger()
+)
+
+const workspacePurgeBatchSize = 100
+
+// StartWorkspacePurge periodically removes workspaces whose restore window has passed,
+// together with everything that belongs to them. It stops when ctx is cancelled.
+func StartWorkspacePurge(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(cfg.WorkspacePurgeInterval)
+		defer ticker.Stop()
+		for {
+			purgeDeletedWorkspaces(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func purgeDeletedWorkspaces(ctx context.Context) {
+	queryOption := queries.NewOptions()
+	queryOption.SetOnlyFields("_id", "image_name", "image_sizes")
+	queryOption.SetPagination(&request.Pagination{Limit: workspacePurgeBatchSize})
+	workspaces, err := queries.NewWorkspace(ctx).GetDeletedBefore(time.Now().Add(-cfg.WorkspaceRestoreWindow), queryOption)
+	if err != nil {
+		return
+	}
+	for _, workspace := range workspaces {
+		if err = queries.NewWorkspaceMember(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewWorkspaceInvitation(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewComment(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewAttachment(ctx).ExpireByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewIssue(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewCounter(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewProject(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewWorkflow(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewSprint(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		if err = queries.NewSavedFilter(ctx).DeleteByWorkspaceId(workspace.Id); err != nil {
+			continue
+		}
+		for _, key := range workspace.ImageKeys() {
+			if err = storage.GetGlobal().DeleteObject(key); err != nil {
+				logger.Error().Err(err).Str("function", "purgeDeletedWorkspaces").Str("functionInline", "storage.GetGlobal().DeleteObject").Msg("workspaceJob")
+			}
+		}
+		if err = queries.NewWorkspace(ctx).DeleteById(workspace.Id); err != nil {
+			continue
+		}
+		logger.Info().Str("workspaceId", workspace.Id.Hex()).Msg("workspace purged")
+	}
+}
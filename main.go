@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -18,8 +19,11 @@ import (
 	"jira-clone-api/common/response"
 	respErr "jira-clone-api/common/response/error"
 	"jira-clone-api/database"
+	"jira-clone-api/jobs"
 	"jira-clone-api/utilities/jwt"
-	"jira-clone-api/utilities/storage_s3"
+	"jira-clone-api/utilities/mailer"
+	"jira-clone-api/utilities/sso"
+	"jira-clone-api/utilities/storage"
 )
 
 var cfg = configure.GetConfig()
@@ -29,16 +33,28 @@ func main() {
 	validator.InitValidateEngine()
 	database.InitDatabase()
 	jwt.New(cfg.TokenPrivateKey, cfg.TokenPublicKey).InitGlobal()
-	storage_s3.New().InitGlobal()
+	storage.New().InitGlobal()
+	mailer.New().InitGlobal()
+	sso.New().InitGlobal()
+	if cfg.ProxyHeader != "" && len(cfg.TrustedProxies) == 0 {
+		logging.GetLogger().Warn().Msg("PROXY_HEADER is ignored without TRUSTED_PROXIES")
+	}
 	app := fiber.New(fiber.Config{
 		ErrorHandler: response.FiberErrorHandler,
 		JSONDecoder:  sonic.Unmarshal,
 		JSONEncoder:  sonic.Marshal,
 		BodyLimit:    cfg.APIBodyLimitSize,
+		// ctx.IP() keys the per-IP login limit, so ProxyHeader is only believed from TrustedProxies.
+		ProxyHeader:             cfg.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.TrustedProxies,
 	})
 	addMiddleware(app)
 	addV1Route(app)
 	handleURLNotFound(app)
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	jobs.StartWorkspacePurge(jobCtx)
+	jobs.StartAttachmentCleanup(jobCtx)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -51,6 +67,7 @@ func main() {
 	}()
 	<-sigChan
 	logging.GetLogger().Info().Msg("Shutting down...")
+	cancelJobs()
 	_ = app.Shutdown()
 	database.DisconnectDatabase()
 }
@@ -62,7 +79,12 @@ func handleURLNotFound(app *fiber.App) {
 }
 
 func addMiddleware(app *fiber.App) {
-	app.Use(cors.New())
+	corsConfig := cors.ConfigDefault
+	if cfg.CorsAllowOrigins != "*" {
+		// Listed origins may send cookies, which the single sign-on callback needs.
+		corsConfig.AllowOrigins, corsConfig.AllowCredentials = cfg.CorsAllowOrigins, true
+	}
+	app.Use(cors.New(corsConfig))
 	if cfg.ElasticAPMEnable {
 		app.Use(logging.FiberApmMiddleware())
 	} else {
@@ -77,4 +99,17 @@ func addV1Route(app *fiber.App) {
 	route := app.Group("/api/jira-clone-api/v1")
 	routers.NewAuthenticate(route).V1()
 	routers.NewWorkspace(route).V1()
+	routers.NewWorkspaceMember(route).V1()
+	routers.NewWorkspaceInvitation(route).V1()
+	routers.NewProject(route).V1()
+	routers.NewIssue(route).V1()
+	routers.NewWorkflow(route).V1()
+	routers.NewBoard(route).V1()
+	routers.NewSprint(route).V1()
+	routers.NewComment(route).V1()
+	routers.NewAttachment(route).V1()
+	routers.NewSavedFilter(route).V1()
+	routers.NewSearch(route).V1()
+	routers.NewAdmin(route).V1()
+	routers.NewStorage(route).V1()
 }
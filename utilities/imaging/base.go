@@ -0,0 +1,30 @@
+package imaging
+
+import (
+	"errors"
+	"image"
+	"io"
+)
+
+// maxDimension bounds decoded images so a small file cannot expand into a huge bitmap.
+const maxDimension = 4096
+
+const (
+	FormatPNG  = "png"
+	FormatJPEG = "jpeg"
+)
+
+var ErrUnsupportedImage = errors.New("image must be a png or jpeg")
+
+type Service interface {
+	Decode(r io.Reader) (img image.Image, format string, err error)
+	Thumbnail(img image.Image, size int) image.Image
+	Encode(w io.Writer, img image.Image, format string) error
+	ContentType(format string) string
+}
+
+type service struct{}
+
+func New() Service {
+	return &service{}
+}
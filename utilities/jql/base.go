@@ -0,0 +1,119 @@
+package jql
+
+import "fmt"
+
+const (
+	// maxQueryLength and maxDepth bound the work a single query can cause.
+	maxQueryLength = 4096
+	maxDepth       = 32
+)
+
+// Logical operators of BinaryExpr.
+const (
+	OperatorAnd = "AND"
+	OperatorOr  = "OR"
+)
+
+// Comparison operators of Clause.
+const (
+	OperatorEqual              = "="
+	OperatorNotEqual           = "!="
+	OperatorGreaterThan        = ">"
+	OperatorGreaterThanOrEqual = ">="
+	OperatorLessThan           = "<"
+	OperatorLessThanOrEqual    = "<="
+	OperatorContains           = "~"
+	OperatorNotContains        = "!~"
+	OperatorIn                 = "IN"
+	OperatorNotIn              = "NOT IN"
+	OperatorIs                 = "IS"
+	OperatorIsNot              = "IS NOT"
+)
+
+// Kinds of Value.
+const (
+	ValueLiteral = iota
+	ValueFunction
+	ValueEmpty
+)
+
+// Position is a 1-based line and column in the query text.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error is a syntax or compile error located in the query text.
+type Error struct {
+	Position
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// NewError returns an Error at pos; compilers use it so their errors point at the query text too.
+func NewError(pos Position, format string, args ...interface{}) *Error {
+	return &Error{Position: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// Node is an expression of the WHERE part: a BinaryExpr, a NotExpr or a Clause.
+type Node interface {
+	Pos() Position
+}
+
+type BinaryExpr struct {
+	Position
+	Operator string
+	Left     Node
+	Right    Node
+}
+
+type NotExpr struct {
+	Position
+	Expr Node
+}
+
+// Clause compares a field with its values, e.g. `priority IN (High, Highest)`. IN and NOT IN carry
+// any number of values, IS and IS NOT a single ValueEmpty and every other operator a single value.
+type Clause struct {
+	Position
+	Field    string
+	Operator string
+	Values   []Value
+}
+
+// Value is a quoted or bare literal, a function call such as currentUser() or EMPTY.
+type Value struct {
+	Position
+	Kind int
+	Text string
+	Args []Value
+}
+
+type SortField struct {
+	Position
+	Field      string
+	Descending bool
+}
+
+// Query is a parsed query. Where is nil when the query only sorts.
+type Query struct {
+	Where   Node
+	OrderBy []SortField
+}
+
+func (p Position) Pos() Position {
+	return p
+}
+
+type Service interface {
+	Parse(input string) (*Query, error)
+}
+
+type service struct{}
+
+func New() Service {
+	return &service{}
+}
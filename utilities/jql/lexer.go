@@ -0,0 +1,142 @@
+package jql
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	tokenEOF = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	Position
+	kind int
+	text string
+}
+
+type lexer struct {
+	input  []rune
+	offset int
+	line   int
+	column int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: []rune(input), line: 1, column: 1}
+}
+
+// tokenize splits the whole input up front; queries are short and the parser needs lookahead.
+func (l *lexer) tokenize() ([]token, error) {
+	var tokens []token
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	for l.offset < len(l.input) && unicode.IsSpace(l.input[l.offset]) {
+		l.advance()
+	}
+	pos := Position{Line: l.line, Column: l.column}
+	if l.offset >= len(l.input) {
+		return token{Position: pos, kind: tokenEOF}, nil
+	}
+	c := l.input[l.offset]
+	switch {
+	case c == '(':
+		l.advance()
+		return token{Position: pos, kind: tokenLeftParen, text: "("}, nil
+	case c == ')':
+		l.advance()
+		return token{Position: pos, kind: tokenRightParen, text: ")"}, nil
+	case c == ',':
+		l.advance()
+		return token{Position: pos, kind: tokenComma, text: ","}, nil
+	case c == '"' || c == '\'':
+		return l.readString(pos, c)
+	case strings.ContainsRune("=!<>~", c):
+		return l.readOperator(pos)
+	case isWordRune(c):
+		start := l.offset
+		for l.offset < len(l.input) && isWordRune(l.input[l.offset]) {
+			l.advance()
+		}
+		return token{Position: pos, kind: tokenWord, text: string(l.input[start:l.offset])}, nil
+	}
+	return token{}, NewError(pos, "unexpected character %q", c)
+}
+
+func (l *lexer) readString(pos Position, quote rune) (token, error) {
+	l.advance()
+	var text strings.Builder
+	for l.offset < len(l.input) {
+		c := l.input[l.offset]
+		l.advance()
+		switch c {
+		case quote:
+			return token{Position: pos, kind: tokenString, text: text.String()}, nil
+		case '\\':
+			if l.offset >= len(l.input) {
+				return token{}, NewError(pos, "unterminated string")
+			}
+			escaped := l.input[l.offset]
+			l.advance()
+			switch escaped {
+			case 'n':
+				text.WriteRune('\n')
+			case 't':
+				text.WriteRune('\t')
+			default:
+				text.WriteRune(escaped)
+			}
+		default:
+			text.WriteRune(c)
+		}
+	}
+	return token{}, NewError(pos, "unterminated string")
+}
+
+func (l *lexer) readOperator(pos Position) (token, error) {
+	c := l.input[l.offset]
+	l.advance()
+	if l.offset < len(l.input) {
+		if op := string([]rune{c, l.input[l.offset]}); op == OperatorNotEqual || op == OperatorNotContains ||
+			op == OperatorGreaterThanOrEqual || op == OperatorLessThanOrEqual {
+			l.advance()
+			return token{Position: pos, kind: tokenOperator, text: op}, nil
+		}
+	}
+	if c == '!' {
+		return token{}, NewError(pos, "expected != or !~")
+	}
+	return token{Position: pos, kind: tokenOperator, text: string(c)}, nil
+}
+
+func (l *lexer) advance() {
+	if l.input[l.offset] == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.offset++
+	l.column++
+}
+
+// isWordRune accepts what bare values need: identifiers, numbers, dates, issue keys and relative
+// dates such as -7d.
+func isWordRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.' || c == '-' || c == '@'
+}
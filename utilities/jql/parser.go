@@ -0,0 +1,280 @@
+package jql
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Parse reads a query of the form
+//
+//	[condition] [ORDER BY field [ASC|DESC] {, field [ASC|DESC]}]
+//
+// where conditions combine clauses with AND, OR, NOT and parentheses. AND binds tighter than OR.
+// Keywords are case-insensitive.
+func (s *service) Parse(input string) (*Query, error) {
+	if utf8.RuneCountInString(input) > maxQueryLength {
+		return nil, NewError(Position{Line: 1, Column: 1}, "query is longer than %d characters", maxQueryLength)
+	}
+	tokens, err := newLexer(input).tokenize()
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	query := &Query{}
+	if p.peek().kind != tokenEOF && !p.isKeyword("ORDER") {
+		if query.Where, err = p.parseOr(0); err != nil {
+			return nil, err
+		}
+	}
+	if p.isKeyword("ORDER") {
+		p.advance()
+		if _, err = p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		if query.OrderBy, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, NewError(tok.Position, "unexpected %q", tok.text)
+	}
+	return query, nil
+}
+
+type parser struct {
+	tokens []token
+	offset int
+}
+
+func (p *parser) parseOr(depth int) (Node, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(OperatorOr) {
+		pos := p.advance().Position
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Position: pos, Operator: OperatorOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (Node, error) {
+	left, err := p.parseNot(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(OperatorAnd) {
+		pos := p.advance().Position
+		right, err := p.parseNot(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Position: pos, Operator: OperatorAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot(depth int) (Node, error) {
+	if depth > maxDepth {
+		return nil, NewError(p.peek().Position, "query is nested deeper than %d levels", maxDepth)
+	}
+	if p.isKeyword("NOT") {
+		pos := p.advance().Position
+		expr, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Position: pos, Expr: expr}, nil
+	}
+	if p.peek().kind == tokenLeftParen {
+		p.advance()
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = p.expect(tokenRightParen, ")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+	return p.parseClause()
+}
+
+func (p *parser) parseClause() (Node, error) {
+	field, err := p.parseField()
+	if err != nil {
+		return nil, err
+	}
+	clause := &Clause{Position: field.Position, Field: field.text}
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenOperator:
+		p.advance()
+		clause.Operator = tok.text
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		clause.Values = []Value{value}
+	case p.isKeyword(OperatorIs):
+		p.advance()
+		clause.Operator = OperatorIs
+		if p.isKeyword("NOT") {
+			p.advance()
+			clause.Operator = OperatorIsNot
+		}
+		if !p.isKeyword("EMPTY") && !p.isKeyword("NULL") {
+			return nil, NewError(p.peek().Position, "expected EMPTY after %s", clause.Operator)
+		}
+		clause.Values = []Value{{Position: p.advance().Position, Kind: ValueEmpty}}
+	case p.isKeyword("IN"), p.isKeyword("NOT"):
+		clause.Operator = OperatorIn
+		if p.isKeyword("NOT") {
+			p.advance()
+			if !p.isKeyword("IN") {
+				return nil, NewError(p.peek().Position, "expected IN after NOT")
+			}
+			clause.Operator = OperatorNotIn
+		}
+		p.advance()
+		if clause.Values, err = p.parseList(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, NewError(tok.Position, "expected an operator after %q", clause.Field)
+	}
+	return clause, nil
+}
+
+// parseList reads `(value, ...)`; a single function call such as openSprints() is accepted as well.
+func (p *parser) parseList() ([]Value, error) {
+	if p.peek().kind != tokenLeftParen {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if value.Kind != ValueFunction {
+			return nil, NewError(value.Position, "expected a list of values")
+		}
+		return []Value{value}, nil
+	}
+	p.advance()
+	var values []Value
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.advance()
+	}
+	if _, err := p.expect(tokenRightParen, ")"); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (p *parser) parseValue() (Value, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenString:
+		p.advance()
+		return Value{Position: tok.Position, Kind: ValueLiteral, Text: tok.text}, nil
+	case tokenWord:
+		if p.isKeyword("EMPTY") || p.isKeyword("NULL") {
+			p.advance()
+			return Value{Position: tok.Position, Kind: ValueEmpty}, nil
+		}
+		p.advance()
+		if p.peek().kind != tokenLeftParen {
+			return Value{Position: tok.Position, Kind: ValueLiteral, Text: tok.text}, nil
+		}
+		p.advance()
+		value := Value{Position: tok.Position, Kind: ValueFunction, Text: tok.text}
+		for p.peek().kind != tokenRightParen {
+			if len(value.Args) > 0 {
+				if _, err := p.expect(tokenComma, ","); err != nil {
+					return Value{}, err
+				}
+			}
+			arg := p.peek()
+			if arg.kind != tokenWord && arg.kind != tokenString {
+				return Value{}, NewError(arg.Position, "expected a function argument")
+			}
+			p.advance()
+			value.Args = append(value.Args, Value{Position: arg.Position, Kind: ValueLiteral, Text: arg.text})
+		}
+		p.advance()
+		return value, nil
+	}
+	return Value{}, NewError(tok.Position, "expected a value")
+}
+
+func (p *parser) parseOrderBy() ([]SortField, error) {
+	var fields []SortField
+	for {
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		sortField := SortField{Position: field.Position, Field: field.text}
+		if p.isKeyword("ASC") {
+			p.advance()
+		} else if p.isKeyword("DESC") {
+			p.advance()
+			sortField.Descending = true
+		}
+		fields = append(fields, sortField)
+		if p.peek().kind != tokenComma {
+			return fields, nil
+		}
+		p.advance()
+	}
+}
+
+func (p *parser) parseField() (token, error) {
+	tok := p.peek()
+	if tok.kind != tokenWord && tok.kind != tokenString {
+		return token{}, NewError(tok.Position, "expected a field name")
+	}
+	p.advance()
+	return tok, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.offset]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.offset]
+	if tok.kind != tokenEOF {
+		p.offset++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *parser) expectKeyword(keyword string) (token, error) {
+	if !p.isKeyword(keyword) {
+		return token{}, NewError(p.peek().Position, "expected %s", keyword)
+	}
+	return p.advance(), nil
+}
+
+func (p *parser) expect(kind int, text string) (token, error) {
+	if p.peek().kind != kind {
+		return token{}, NewError(p.peek().Position, "expected %q", text)
+	}
+	return p.advance(), nil
+}
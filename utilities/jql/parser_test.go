@@ -0,0 +1,141 @@
+package jql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// format prints node with every binary expression in parentheses, so the tests see how it was grouped.
+func format(node Node) string {
+	switch node := node.(type) {
+	case *BinaryExpr:
+		return fmt.Sprintf("(%s %s %s)", format(node.Left), node.Operator, format(node.Right))
+	case *NotExpr:
+		return "NOT " + format(node.Expr)
+	case *Clause:
+		values := make([]string, len(node.Values))
+		for i, value := range node.Values {
+			values[i] = formatValue(value)
+		}
+		if node.Operator == OperatorIn || node.Operator == OperatorNotIn {
+			return fmt.Sprintf("%s %s (%s)", node.Field, node.Operator, strings.Join(values, ", "))
+		}
+		return fmt.Sprintf("%s %s %s", node.Field, node.Operator, values[0])
+	}
+	return "?"
+}
+
+func formatValue(value Value) string {
+	switch value.Kind {
+	case ValueEmpty:
+		return "EMPTY"
+	case ValueFunction:
+		args := make([]string, len(value.Args))
+		for i, arg := range value.Args {
+			args[i] = formatValue(arg)
+		}
+		return fmt.Sprintf("%s(%s)", value.Text, strings.Join(args, ", "))
+	}
+	return fmt.Sprintf("%q", value.Text)
+}
+
+func formatOrderBy(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Field + " ASC"
+		if field.Descending {
+			parts[i] = field.Field + " DESC"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		where   string
+		orderBy string
+	}{
+		{`status = Done`, `status = "Done"`, ""},
+		{`a = 1 OR b = 2 AND c = 3`, `(a = "1" OR (b = "2" AND c = "3"))`, ""},
+		{`(a = 1 OR b = 2) AND c = 3`, `((a = "1" OR b = "2") AND c = "3")`, ""},
+		{`a = 1 AND b = 2 AND c = 3`, `((a = "1" AND b = "2") AND c = "3")`, ""},
+		{`NOT a = 1 AND b = 2`, `(NOT a = "1" AND b = "2")`, ""},
+		{`NOT (a = 1 OR b = 2)`, `NOT (a = "1" OR b = "2")`, ""},
+		{`assignee not in (alice, "bob smith")`, `assignee NOT IN ("alice", "bob smith")`, ""},
+		{`sprint IN openSprints()`, `sprint IN (openSprints())`, ""},
+		{`assignee is not empty`, `assignee IS NOT EMPTY`, ""},
+		{`assignee IS NULL`, `assignee IS EMPTY`, ""},
+		{`assignee = currentUser()`, `assignee = currentUser()`, ""},
+		{`created >= startOfDay(-1d, 'x')`, `created >= startOfDay("-1d", "x")`, ""},
+		{`summary ~ "say \"hi\""`, `summary ~ "say \"hi\""`, ""},
+		{`labels != 'a b' and due <= -7d`, `(labels != "a b" AND due <= "-7d")`, ""},
+		{`"story points" > 3`, `story points > "3"`, ""},
+		{`ORDER BY created DESC, priority`, "", "created DESC, priority ASC"},
+		{`status = Done order by rank asc`, `status = "Done"`, "rank ASC"},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			query, err := New().Parse(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			where := ""
+			if query.Where != nil {
+				where = format(query.Where)
+			}
+			if where != test.where {
+				t.Errorf("where: got %s, want %s", where, test.where)
+			}
+			if orderBy := formatOrderBy(query.OrderBy); orderBy != test.orderBy {
+				t.Errorf("order by: got %s, want %s", orderBy, test.orderBy)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		line    int
+		column  int
+		message string
+	}{
+		{`status =`, 1, 9, "expected a value"},
+		{`status = Done AND`, 1, 18, "expected a field name"},
+		{`(a = 1`, 1, 7, `expected ")"`},
+		{`a = 1 b = 2`, 1, 7, `unexpected "b"`},
+		{"a = 1\nAND b ! 2", 2, 7, "expected != or !~"},
+		{"a = 1 AND\n\n  b # 2", 3, 5, "unexpected character '#'"},
+		{`a = 'open`, 1, 5, "unterminated string"},
+		{`a IN b`, 1, 6, "expected a list of values"},
+		{`a IS 1`, 1, 6, "expected EMPTY after IS"},
+		{`a IS NOT`, 1, 9, "expected EMPTY after IS NOT"},
+		{`a NOT b`, 1, 7, "expected IN after NOT"},
+		{`a b`, 1, 3, `expected an operator after "a"`},
+		{`a = f(x y)`, 1, 9, `expected ","`},
+		{`ORDER status`, 1, 7, "expected BY"},
+		{`a = 1 ORDER BY`, 1, 15, "expected a field name"},
+		{strings.Repeat("NOT ", maxDepth+2) + "a = 1", 1, 4*(maxDepth+1) + 1, fmt.Sprintf("query is nested deeper than %d levels", maxDepth)},
+		{strings.Repeat("a", maxQueryLength+1), 1, 1, fmt.Sprintf("query is longer than %d characters", maxQueryLength)},
+	}
+	for _, test := range tests {
+		name := test.input
+		if len(name) > 40 {
+			name = name[:40]
+		}
+		t.Run(name, func(t *testing.T) {
+			_, err := New().Parse(test.input)
+			var jqlErr *Error
+			if !errors.As(err, &jqlErr) {
+				t.Fatalf("got %v, want a jql error", err)
+			}
+			if jqlErr.Line != test.line || jqlErr.Column != test.column || jqlErr.Message != test.message {
+				t.Fatalf("got %d:%d %q, want %d:%d %q", jqlErr.Line, jqlErr.Column, jqlErr.Message, test.line, test.column, test.message)
+			}
+		})
+	}
+}
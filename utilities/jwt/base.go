@@ -13,9 +13,18 @@ const (
 	TokenTypeRefresh = "refresh"
 )
 
+const (
+	TransactionWorkspaceInvitation = "workspace_invitation"
+	// TransactionMfaPending is the purpose of the token Login hands out when a second factor is still
+	// owed; its transaction id is the user id.
+	TransactionMfaPending = "mfa_pending"
+)
+
 var (
 	global Service
 	logger = logging.GetLogger()
+
+	ErrTokenExpired = jwt.ErrTokenExpired
 )
 
 type Service interface {
@@ -23,6 +32,8 @@ type Service interface {
 	GenerateToken(tokenId string, isRefreshToken bool, duration time.Duration) (tokenStr string, err error)
 	GeneratePairToken(tokenId string, accessTokenDuration time.Duration, refreshTokenDuration time.Duration) (accessToken string, refreshToken string, err error)
 	ValidateToken(token string) (data *Payload, err error)
+	GenerateTransactionToken(transactionId string, purpose string, duration time.Duration) (tokenStr string, err error)
+	ValidateTransactionToken(token string, purpose string) (transactionId string, err error)
 }
 
 type service struct {
@@ -21,6 +21,10 @@ func (p Payload) IsRefreshToken() bool {
 	return p.TokenType == TokenTypeRefresh
 }
 
+func (p Payload) IsMfaPendingToken() bool {
+	return p.Subject == TransactionMfaPending
+}
+
 type payloadTransaction struct {
 	jwt.RegisteredClaims
 }
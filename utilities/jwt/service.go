@@ -41,17 +41,44 @@ func (s *service) GeneratePairToken(tokenId string, accessTokenDuration time.Dur
 }
 
 func (s *service) ValidateToken(token string) (data *Payload, err error) {
-	keyFunc := func(token *jwt.Token) (interface{}, error) {
-		_, ok := token.Method.(*jwt.SigningMethodEd25519)
-		if !ok {
-			return nil, errors.New("token validate failed")
-		}
-		return s.publicKey, nil
-	}
-
 	payload := new(Payload)
-	if _, err = jwt.ParseWithClaims(token, payload, keyFunc); err != nil {
+	if _, err = jwt.ParseWithClaims(token, payload, s.keyFunc); err != nil {
 		return nil, err
 	}
 	return payload, nil
 }
+
+// GenerateTransactionToken signs a one-purpose code (e.g. an invitation) pointing to the transaction document.
+func (s *service) GenerateTransactionToken(transactionId string, purpose string, duration time.Duration) (tokenStr string, err error) {
+	if transactionId == "" {
+		return "", errors.New("transactionId is empty")
+	}
+	payload := payloadTransaction{
+		RegisteredClaims: jwt.RegisteredClaims{
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			Subject:   purpose,
+			ID:        transactionId,
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, payload)
+	return token.SignedString(s.privateKey)
+}
+
+// ValidateTransactionToken returns the transaction id of a code signed for the given purpose.
+// An expired code is reported with ErrTokenExpired.
+func (s *service) ValidateTransactionToken(token string, purpose string) (transactionId string, err error) {
+	payload := new(payloadTransaction)
+	if _, err = jwt.ParseWithClaims(token, payload, s.keyFunc, jwt.WithSubject(purpose)); err != nil {
+		return "", err
+	}
+	return payload.ID, nil
+}
+
+func (s *service) keyFunc(token *jwt.Token) (interface{}, error) {
+	_, ok := token.Method.(*jwt.SigningMethodEd25519)
+	if !ok {
+		return nil, errors.New("token validate failed")
+	}
+	return s.publicKey, nil
+}
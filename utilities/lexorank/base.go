@@ -0,0 +1,21 @@
+package lexorank
+
+const (
+	alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+	base     = len(alphabet)
+
+	// RebalanceLength is the rank length past which a column should be respread.
+	RebalanceLength = 12
+)
+
+type Service interface {
+	Between(prev, next string) string
+	Spread(count int) []string
+	NeedsRebalance(rank string) bool
+}
+
+type service struct{}
+
+func New() Service {
+	return &service{}
+}
@@ -0,0 +1,73 @@
+package lexorank
+
+import "strings"
+
+// Between returns a rank strictly between prev and next. An empty prev means the start of
+// the column and an empty next means its end. Ranks are base-36 fractions without trailing
+// zeros, so there is always room for another rank below any of them.
+func (s *service) Between(prev, next string) string {
+	if next != "" {
+		n := 0
+		for n < len(next) && digitAt(prev, n) == digit(next[n]) {
+			n++
+		}
+		if n > 0 {
+			return next[:n] + s.Between(suffix(prev, n), next[n:])
+		}
+	}
+	prevDigit := digitAt(prev, 0)
+	nextDigit := base
+	if next != "" {
+		nextDigit = digit(next[0])
+	}
+	if nextDigit-prevDigit > 1 {
+		return string(alphabet[(prevDigit+nextDigit)/2])
+	}
+	if len(next) > 1 {
+		return next[:1]
+	}
+	return string(alphabet[prevDigit]) + s.Between(suffix(prev, 1), "")
+}
+
+// Spread returns count evenly spaced, increasing ranks, leaving a digit of room between neighbours.
+func (s *service) Spread(count int) []string {
+	width, capacity := 1, base
+	for capacity < (count+1)*base {
+		width++
+		capacity *= base
+	}
+	step := capacity / (count + 1)
+	ranks := make([]string, count)
+	buffer := make([]byte, width)
+	for i := 0; i < count; i++ {
+		value := (i + 1) * step
+		for j := width - 1; j >= 0; j-- {
+			buffer[j] = alphabet[value%base]
+			value /= base
+		}
+		ranks[i] = strings.TrimRight(string(buffer), "0")
+	}
+	return ranks
+}
+
+func (s *service) NeedsRebalance(rank string) bool {
+	return len(rank) > RebalanceLength
+}
+
+func digit(c byte) int {
+	return strings.IndexByte(alphabet, c)
+}
+
+func digitAt(rank string, i int) int {
+	if i < len(rank) {
+		return digit(rank[i])
+	}
+	return 0
+}
+
+func suffix(rank string, i int) string {
+	if i < len(rank) {
+		return rank[i:]
+	}
+	return ""
+}
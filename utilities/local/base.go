@@ -15,6 +15,10 @@ type Service interface {
 	SetStatusCode(value int)
 	SetTokenId(value primitive.ObjectID)
 	GetTokenId() primitive.ObjectID
+	SetWorkspace(value models.Workspace)
+	GetWorkspace() models.Workspace
+	SetWorkspaceRole(value string)
+	GetWorkspaceRole() string
 }
 
 const (
@@ -22,6 +26,9 @@ const (
 	KeyUser       = "user"
 	KeyExtraBody  = "extraBody"
 	KeyStatusCode = "statusCode"
+
+	KeyWorkspace     = "workspace"
+	KeyWorkspaceRole = "workspaceRole"
 )
 
 type service struct {
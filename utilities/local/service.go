@@ -49,3 +49,25 @@ func (s service) GetTokenId() primitive.ObjectID {
 	}
 	return primitive.NilObjectID
 }
+
+func (s service) SetWorkspace(value models.Workspace) {
+	s.context.Locals(KeyWorkspace, value)
+}
+
+func (s service) GetWorkspace() models.Workspace {
+	if value, ok := s.context.Locals(KeyWorkspace).(models.Workspace); ok {
+		return value
+	}
+	return models.Workspace{}
+}
+
+func (s service) SetWorkspaceRole(value string) {
+	s.context.Locals(KeyWorkspaceRole, value)
+}
+
+func (s service) GetWorkspaceRole() string {
+	if value, ok := s.context.Locals(KeyWorkspaceRole).(string); ok {
+		return value
+	}
+	return ""
+}
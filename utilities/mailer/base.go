@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"jira-clone-api/common/configure"
+	"jira-clone-api/common/logging"
+)
+
+var (
+	global Service
+	logger = logging.GetLogger()
+	cfg    = configure.GetConfig()
+)
+
+// Drivers selectable through configure.Configuration.MailerDriver.
+const (
+	DriverSMTP   = "smtp"
+	DriverFile   = "file"
+	DriverMemory = "memory"
+)
+
+// Message is a plain-text email sent from configure.Configuration.MailerFrom.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Service interface {
+	InitGlobal()
+	Send(message Message) error
+}
+
+// New returns the driver named by the configuration. The file and memory drivers never deliver
+// anything and are meant for local runs, where the links they contain can be read from disk or logs.
+func New() Service {
+	switch cfg.MailerDriver {
+	case DriverSMTP:
+		return newSMTP()
+	case DriverFile:
+		return newFile(cfg.MailerFilePath)
+	case DriverMemory:
+		return newMemory()
+	}
+	logger.Fatal().Str("driver", cfg.MailerDriver).Msg("Mailer driver is not supported")
+	return nil
+}
+
+func GetGlobal() Service {
+	return global
+}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fileService writes every message as an .eml file under root instead of sending it.
+type fileService struct {
+	root string
+	from string
+}
+
+func newFile(root string) Service {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		logger.Fatal().Err(err).Str("path", root).Msg("Mailer directory is not writable")
+	}
+	return &fileService{root: root, from: cfg.MailerFrom}
+}
+
+func (s *fileService) InitGlobal() {
+	global = s
+}
+
+func (s *fileService) Send(message Message) error {
+	data, err := build(s.from, message)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405"), primitive.NewObjectID().Hex())
+	return os.WriteFile(filepath.Join(s.root, name), data, 0o640)
+}
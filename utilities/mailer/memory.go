@@ -0,0 +1,21 @@
+package mailer
+
+// memoryService sends nothing and writes nothing to disk: messages, including the links they carry,
+// only go to the log, so local runs need neither a relay nor a writable directory.
+type memoryService struct{}
+
+func newMemory() Service {
+	return &memoryService{}
+}
+
+func (s *memoryService) InitGlobal() {
+	global = s
+}
+
+func (s *memoryService) Send(message Message) error {
+	if _, err := build(cfg.MailerFrom, message); err != nil {
+		return err
+	}
+	logger.Info().Str("to", message.To).Str("subject", message.Subject).Str("body", message.Body).Msg("mail")
+	return nil
+}
@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// build renders message as an RFC 5322 document. Header values are checked for line breaks so a
+// recipient or subject taken from user input cannot inject extra headers.
+func build(from string, message Message) ([]byte, error) {
+	if strings.ContainsAny(message.To+message.Subject, "\r\n") {
+		return nil, fmt.Errorf("header values must not contain line breaks")
+	}
+	if _, err := mail.ParseAddress(message.To); err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "From: %s\r\n", from)
+	fmt.Fprintf(&buffer, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buffer.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buffer.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return buffer.Bytes(), nil
+}
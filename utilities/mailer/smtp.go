@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"net"
+	"net/mail"
+	"net/smtp"
+)
+
+// smtpService delivers through an SMTP relay, authenticating with PLAIN when a username is set.
+// net/smtp upgrades to TLS whenever the server offers STARTTLS.
+type smtpService struct {
+	address  string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func newSMTP() Service {
+	return &smtpService{
+		address:  net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		host:     cfg.SMTPHost,
+		username: cfg.SMTPUsername,
+		password: cfg.SMTPPassword,
+		from:     cfg.MailerFrom,
+	}
+}
+
+func (s *smtpService) InitGlobal() {
+	global = s
+}
+
+func (s *smtpService) Send(message Message) error {
+	data, err := build(s.from, message)
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	return smtp.SendMail(s.address, auth, from.Address, []string{message.To}, data)
+}
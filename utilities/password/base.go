@@ -0,0 +1,45 @@
+package password
+
+import (
+	"errors"
+
+	"jira-clone-api/common/configure"
+)
+
+var (
+	cfg = configure.GetConfig()
+
+	ErrTooShort           = errors.New("password is too short")
+	ErrTooLong            = errors.New("password is too long")
+	ErrMissingLower       = errors.New("password needs a lower-case letter")
+	ErrMissingUpper       = errors.New("password needs an upper-case letter")
+	ErrMissingDigit       = errors.New("password needs a digit")
+	ErrMissingSymbol      = errors.New("password needs a symbol")
+	ErrTooCommon          = errors.New("password is too common")
+	ErrHashNotSupported   = errors.New("password hash format is not supported")
+	ErrHashInvalid        = errors.New("password hash is malformed")
+	ErrArgon2Incompatible = errors.New("argon2 version is not supported")
+)
+
+type Service interface {
+	CheckPolicy(password string) error
+	Hash(password string) (encoded string, err error)
+	Verify(password, encoded string) (ok bool, err error)
+	NeedsRehash(encoded string) bool
+}
+
+type service struct {
+	params argon2Params
+}
+
+func New() Service {
+	return &service{
+		params: argon2Params{
+			memory:      cfg.PasswordArgon2Memory,
+			iterations:  cfg.PasswordArgon2Iterations,
+			parallelism: cfg.PasswordArgon2Parallelism,
+			saltLength:  16,
+			keyLength:   32,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// commonPasswordsFile lists widely used and breached passwords, one lower-case entry per line.
+//
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = loadCommonPasswords()
+
+// CheckPolicy enforces the configured length and character classes, then rejects passwords found in
+// the bundled common list, also once trailing digits and symbols are removed ("Summer2024!" counts as
+// "summer").
+func (s *service) CheckPolicy(password string) error {
+	length := utf8.RuneCountInString(password)
+	switch {
+	case length < cfg.PasswordMinLength:
+		return ErrTooShort
+	case length > cfg.PasswordMaxLength:
+		return ErrTooLong
+	}
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	switch {
+	case cfg.PasswordRequireLower && !hasLower:
+		return ErrMissingLower
+	case cfg.PasswordRequireUpper && !hasUpper:
+		return ErrMissingUpper
+	case cfg.PasswordRequireDigit && !hasDigit:
+		return ErrMissingDigit
+	case cfg.PasswordRequireSymbol && !hasSymbol:
+		return ErrMissingSymbol
+	}
+	if isCommon(password) {
+		return ErrTooCommon
+	}
+	return nil
+}
+
+func isCommon(password string) bool {
+	lowered := strings.ToLower(password)
+	if _, ok := commonPasswords[lowered]; ok {
+		return true
+	}
+	stem := strings.TrimRightFunc(lowered, func(r rune) bool { return !unicode.IsLetter(r) })
+	stem = strings.TrimLeftFunc(stem, func(r rune) bool { return !unicode.IsLetter(r) })
+	if utf8.RuneCountInString(stem) < 4 {
+		return false
+	}
+	_, ok := commonPasswords[stem]
+	return ok
+}
+
+func loadCommonPasswords() map[string]struct{} {
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(commonPasswordsFile))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords
+}
@@ -0,0 +1,176 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"jira-clone-api/common/configure"
+	"jira-clone-api/common/logging"
+)
+
+// The configuration and the logger are read when used rather than at init, so the providers can be
+// tested without the token keys the configuration requires.
+var (
+	global Service
+
+	ErrProviderNotFound   = errors.New("sso provider is not configured")
+	ErrExchangeFailed     = errors.New("sso code exchange failed")
+	ErrIdTokenInvalid     = errors.New("sso id token is invalid")
+	ErrDiscoveryInvalid   = errors.New("sso discovery document is invalid")
+	ErrEmailNotVerified   = errors.New("sso email is not verified")
+	ErrAccountNotLinkable = errors.New("sso account cannot be linked")
+)
+
+// Providers selectable through the SsoGoogle, SsoGithub and SsoOidc fields of configure.Configuration.
+const (
+	ProviderGoogle = "google"
+	ProviderGithub = "github"
+	ProviderOidc   = "oidc"
+)
+
+const (
+	googleIssuer = "https://accounts.google.com"
+	// httpTimeout bounds every call made to a provider.
+	httpTimeout = 10 * time.Second
+	// metadataTTL is how long discovery documents and signing keys are trusted before being fetched again.
+	metadataTTL = time.Hour
+	// keysRefreshInterval limits how often an unknown key id makes the signing keys be fetched early,
+	// so that tokens with made-up key ids cannot hammer the provider.
+	keysRefreshInterval = time.Minute
+)
+
+// Identity is what a provider vouches for about the person who logged in. Subject is stable for
+// the account at that provider, unlike the email.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	Name          string
+}
+
+// LinkTarget picks the local account an unknown identity may be linked to, given for each account
+// sharing its email whether that email is verified locally. It returns -1 when there is no such
+// account and a new one should be created. The email must be verified on both sides and match a
+// single account: whoever registered an unverified local email may not own it and would keep their
+// password on the account.
+func (identity Identity) LinkTarget(emailVerified []bool) (int, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return 0, ErrEmailNotVerified
+	}
+	if len(emailVerified) == 0 {
+		return -1, nil
+	}
+	target := -1
+	for i, verified := range emailVerified {
+		if !verified {
+			continue
+		}
+		if target >= 0 {
+			return 0, ErrAccountNotLinkable
+		}
+		target = i
+	}
+	if target < 0 {
+		return 0, ErrAccountNotLinkable
+	}
+	return target, nil
+}
+
+// AuthRequest holds the secrets of one login: State is echoed back by the provider, Nonce is bound
+// into the OIDC ID token and CodeVerifier is the PKCE proof that the code exchange comes from us.
+type AuthRequest struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+type Provider interface {
+	// AuthCodeURL is where the browser is sent to log in at the provider.
+	AuthCodeURL(ctx context.Context, request AuthRequest) (string, error)
+	// Exchange trades the code the provider redirected back with for the identity of the user.
+	Exchange(ctx context.Context, code string, request AuthRequest) (*Identity, error)
+}
+
+type Service interface {
+	InitGlobal()
+	Providers() []string
+	Provider(name string) (Provider, error)
+	NewAuthRequest() (AuthRequest, error)
+}
+
+type service struct {
+	providers map[string]Provider
+}
+
+// New builds the providers that have a client id configured. Each one redirects back to
+// SsoRedirectUrl followed by its name.
+func New() Service {
+	cfg := configure.GetConfig()
+	client := &http.Client{Timeout: httpTimeout}
+	providers := map[string]Provider{}
+	if cfg.SsoGoogle.ClientId != "" {
+		google := cfg.SsoGoogle
+		if google.Issuer == "" {
+			google.Issuer = googleIssuer
+		}
+		providers[ProviderGoogle] = newOidc(google, redirectUrl(cfg, ProviderGoogle), client)
+	}
+	if cfg.SsoGithub.ClientId != "" {
+		providers[ProviderGithub] = newGithub(cfg.SsoGithub, redirectUrl(cfg, ProviderGithub), client)
+	}
+	if cfg.SsoOidc.ClientId != "" {
+		if cfg.SsoOidc.Issuer == "" {
+			logging.GetLogger().Fatal().Msg("SSO_OIDC_ISSUER is required when SSO_OIDC_CLIENT_ID is set")
+		}
+		providers[ProviderOidc] = newOidc(cfg.SsoOidc, redirectUrl(cfg, ProviderOidc), client)
+	}
+	return &service{providers: providers}
+}
+
+func GetGlobal() Service {
+	return global
+}
+
+func (s *service) InitGlobal() {
+	global = s
+}
+
+func (s *service) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (s *service) Provider(name string) (Provider, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+	return provider, nil
+}
+
+func (s *service) NewAuthRequest() (AuthRequest, error) {
+	var (
+		request AuthRequest
+		err     error
+	)
+	if request.State, err = randomString(); err != nil {
+		return request, err
+	}
+	if request.Nonce, err = randomString(); err != nil {
+		return request, err
+	}
+	request.CodeVerifier, err = randomString()
+	return request, err
+}
+
+func redirectUrl(cfg configure.Configuration, name string) string {
+	return cfg.SsoRedirectUrl + "/" + name
+}
@@ -0,0 +1,131 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxResponseSize caps what is read from a provider; no legitimate answer comes close.
+const maxResponseSize = 1 << 20
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IdToken          string `json:"id_token"`
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// oauth2Client is the authorization code flow with PKCE (RFC 6749 and RFC 7636) shared by providers.
+type oauth2Client struct {
+	clientId     string
+	clientSecret string
+	redirectUrl  string
+	scopes       []string
+	http         *http.Client
+}
+
+func (c *oauth2Client) authCodeURL(endpoint string, request AuthRequest, extra url.Values) (string, error) {
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	query := target.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", c.clientId)
+	query.Set("redirect_uri", c.redirectUrl)
+	query.Set("scope", strings.Join(c.scopes, " "))
+	query.Set("state", request.State)
+	query.Set("code_challenge", codeChallenge(request.CodeVerifier))
+	query.Set("code_challenge_method", "S256")
+	for key, values := range extra {
+		query[key] = values
+	}
+	target.RawQuery = query.Encode()
+	return target.String(), nil
+}
+
+func (c *oauth2Client) exchange(ctx context.Context, endpoint, code string, request AuthRequest, basicAuth bool) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.redirectUrl)
+	form.Set("code_verifier", request.CodeVerifier)
+	if !basicAuth {
+		form.Set("client_id", c.clientId)
+		form.Set("client_secret", c.clientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basicAuth {
+		req.SetBasicAuth(url.QueryEscape(c.clientId), url.QueryEscape(c.clientSecret))
+	}
+	var token tokenResponse
+	if err = c.do(req, &token); err != nil && token.Error == "" {
+		return nil, err
+	}
+	// GitHub reports errors with a 200 status, so the error field is checked whatever the status.
+	if token.Error != "" {
+		return nil, fmt.Errorf("%w: %s", ErrExchangeFailed, strings.TrimSpace(token.Error+" "+token.ErrorDescription))
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%w: no access token", ErrExchangeFailed)
+	}
+	return &token, nil
+}
+
+// getJSON fetches endpoint, authenticated with accessToken when it is set.
+func (c *oauth2Client) getJSON(ctx context.Context, endpoint, accessToken string, data interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return c.do(req, data)
+}
+
+// do decodes the JSON body into data even for error statuses, which carry the OAuth2 error fields.
+func (c *oauth2Client) do(req *http.Request, data interface{}) error {
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	decodeErr := json.Unmarshal(body, data)
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: status %d", req.Method, req.URL.Redacted(), res.StatusCode)
+	}
+	return decodeErr
+}
+
+func randomString() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
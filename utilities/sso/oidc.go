@@ -0,0 +1,278 @@
+package sso
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"jira-clone-api/common/configure"
+	"jira-clone-api/common/logging"
+)
+
+// idTokenMethods are the signature algorithms accepted on ID tokens. Symmetric algorithms and
+// "none" are never accepted, whatever the discovery document says.
+var idTokenMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// idTokenLeeway absorbs the clock drift between us and the provider.
+const idTokenLeeway = time.Minute
+
+type discoveryDocument struct {
+	Issuer                   string   `json:"issuer"`
+	AuthorizationEndpoint    string   `json:"authorization_endpoint"`
+	TokenEndpoint            string   `json:"token_endpoint"`
+	UserinfoEndpoint         string   `json:"userinfo_endpoint"`
+	JwksUri                  string   `json:"jwks_uri"`
+	TokenEndpointAuthMethods []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// flexibleBool decodes booleans that some providers send as the strings "true" and "false".
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case bool:
+		*b = flexibleBool(value)
+	case string:
+		*b = flexibleBool(value == "true")
+	}
+	return nil
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string       `json:"nonce"`
+	AuthorizedParty   string       `json:"azp"`
+	Email             string       `json:"email"`
+	EmailVerified     flexibleBool `json:"email_verified"`
+	PreferredUsername string       `json:"preferred_username"`
+	Name              string       `json:"name"`
+}
+
+type userinfoClaims struct {
+	Subject           string       `json:"sub"`
+	Email             string       `json:"email"`
+	EmailVerified     flexibleBool `json:"email_verified"`
+	PreferredUsername string       `json:"preferred_username"`
+	Name              string       `json:"name"`
+}
+
+// oidcProvider logs in through OpenID Connect. Its endpoints come from the discovery document of the
+// issuer, and the document and the signing keys are cached for metadataTTL. mutex only guards the
+// cache; fetches run without it, and while one request refreshes a cached value the others keep
+// using the old one.
+type oidcProvider struct {
+	oauth2Client
+	issuer string
+
+	mutex               sync.Mutex
+	discovery           *discoveryDocument
+	discoveredAt        time.Time
+	discoveryRefreshing bool
+	keys                map[string]crypto.PublicKey
+	keysFetchedAt       time.Time
+	keysRefreshing      bool
+}
+
+func newOidc(provider configure.SsoProvider, redirectUrl string, client *http.Client) Provider {
+	scopes := provider.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+	return &oidcProvider{
+		oauth2Client: oauth2Client{
+			clientId:     provider.ClientId,
+			clientSecret: provider.ClientSecret,
+			redirectUrl:  redirectUrl,
+			scopes:       scopes,
+			http:         client,
+		},
+		issuer: strings.TrimSuffix(provider.Issuer, "/"),
+	}
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, request AuthRequest) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	return p.authCodeURL(discovery.AuthorizationEndpoint, request, url.Values{"nonce": {request.Nonce}})
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code string, request AuthRequest) (*Identity, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// client_secret_basic is the default of the spec; only providers that rule it out get the secret in the body.
+	basicAuth := len(discovery.TokenEndpointAuthMethods) == 0 || slices.Contains(discovery.TokenEndpointAuthMethods, "client_secret_basic")
+	token, err := p.exchange(ctx, discovery.TokenEndpoint, code, request, basicAuth)
+	if err != nil {
+		return nil, err
+	}
+	if token.IdToken == "" {
+		return nil, fmt.Errorf("%w: missing", ErrIdTokenInvalid)
+	}
+	claims, err := p.verifyIdToken(ctx, discovery, token.IdToken, request.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	identity := &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Username:      claims.PreferredUsername,
+		Name:          claims.Name,
+	}
+	// Some providers keep the profile out of the ID token and only serve it from the userinfo endpoint.
+	if identity.Email == "" && discovery.UserinfoEndpoint != "" {
+		var userinfo userinfoClaims
+		if err = p.getJSON(ctx, discovery.UserinfoEndpoint, token.AccessToken, &userinfo); err != nil {
+			return nil, err
+		}
+		if userinfo.Subject != identity.Subject {
+			return nil, fmt.Errorf("%w: userinfo is about another subject", ErrIdTokenInvalid)
+		}
+		identity.Email, identity.EmailVerified = userinfo.Email, bool(userinfo.EmailVerified)
+		if identity.Username == "" {
+			identity.Username = userinfo.PreferredUsername
+		}
+		if identity.Name == "" {
+			identity.Name = userinfo.Name
+		}
+	}
+	return identity, nil
+}
+
+// verifyIdToken checks the signature, issuer, audience, lifetime and nonce of an ID token as
+// OpenID Connect Core 3.1.3.7 requires.
+func (p *oidcProvider) verifyIdToken(ctx context.Context, discovery *discoveryDocument, idToken, nonce string) (*idTokenClaims, error) {
+	claims := new(idTokenClaims)
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		keyId, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, discovery, keyId)
+	},
+		jwt.WithValidMethods(idTokenMethods),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.clientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIdTokenInvalid, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrIdTokenInvalid)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.clientId {
+		return nil, fmt.Errorf("%w: issued to another party", ErrIdTokenInvalid)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrIdTokenInvalid)
+	}
+	return claims, nil
+}
+
+// getDiscovery returns the cached discovery document, fetching it when it is missing or stale.
+// A provider that is briefly down keeps being served from the stale copy.
+func (p *oidcProvider) getDiscovery(ctx context.Context) (*discoveryDocument, error) {
+	p.mutex.Lock()
+	cached := p.discovery
+	refresh := cached == nil || time.Since(p.discoveredAt) >= metadataTTL && !p.discoveryRefreshing
+	if refresh {
+		p.discoveryRefreshing = true
+	}
+	p.mutex.Unlock()
+	if !refresh {
+		return cached, nil
+	}
+	discovery, err := p.fetchDiscovery(ctx)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.discoveryRefreshing = false
+	if err != nil {
+		if p.discovery != nil {
+			logging.GetLogger().Warn().Err(err).Str("issuer", p.issuer).Msg("SSO discovery refresh failed, keeping the cached document")
+			return p.discovery, nil
+		}
+		return nil, err
+	}
+	p.discovery, p.discoveredAt = discovery, time.Now()
+	return discovery, nil
+}
+
+func (p *oidcProvider) fetchDiscovery(ctx context.Context) (*discoveryDocument, error) {
+	discovery := new(discoveryDocument)
+	if err := p.getJSON(ctx, p.issuer+"/.well-known/openid-configuration", "", discovery); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("%w: issuer %q does not match %q", ErrDiscoveryInvalid, discovery.Issuer, p.issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksUri == "" {
+		return nil, fmt.Errorf("%w: endpoints are missing", ErrDiscoveryInvalid)
+	}
+	return discovery, nil
+}
+
+// getKey returns the signing key with the given id. The key set is fetched again when it is stale,
+// or early when the id is unknown because the provider may have rotated its keys. Until the first
+// fetch succeeds every request fetches, since there is nothing cached to fall back on.
+func (p *oidcProvider) getKey(ctx context.Context, discovery *discoveryDocument, keyId string) (crypto.PublicKey, error) {
+	p.mutex.Lock()
+	key, ok := p.lookupKey(keyId)
+	age := time.Since(p.keysFetchedAt)
+	refresh := p.keysFetchedAt.IsZero() || (age >= metadataTTL || !ok && age >= keysRefreshInterval) && !p.keysRefreshing
+	if refresh {
+		p.keysRefreshing = true
+	}
+	p.mutex.Unlock()
+	if !refresh {
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", keyId)
+		}
+		return key, nil
+	}
+	var keySet jsonWebKeySet
+	err := p.getJSON(ctx, discovery.JwksUri, "", &keySet)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.keysRefreshing = false
+	if err != nil {
+		if ok {
+			logging.GetLogger().Warn().Err(err).Str("issuer", p.issuer).Msg("SSO signing keys refresh failed, keeping the cached keys")
+			return key, nil
+		}
+		return nil, err
+	}
+	p.keys, p.keysFetchedAt = keySet.publicKeys(), time.Now()
+	if key, ok = p.lookupKey(keyId); !ok {
+		return nil, fmt.Errorf("unknown signing key %q", keyId)
+	}
+	return key, nil
+}
+
+// lookupKey finds a key in the cached set. A token without a key id is only accepted when the set
+// holds a single key, since there is then no doubt which one signed it.
+func (p *oidcProvider) lookupKey(keyId string) (crypto.PublicKey, bool) {
+	if keyId == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[keyId]
+	return key, ok
+}
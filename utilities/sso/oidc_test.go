@@ -0,0 +1,386 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"jira-clone-api/common/configure"
+)
+
+const testClientId = "jira-clone"
+
+// fakeIssuer is an OpenID provider serving discovery, its signing keys and a token endpoint that
+// checks the PKCE verifier against the challenge of the last authorization request.
+type fakeIssuer struct {
+	server *httptest.Server
+
+	mutex       sync.Mutex
+	keys        map[string]*rsa.PrivateKey
+	jwksFetches int
+	jwksHold    chan chan struct{}
+	challenge   string
+	idToken     string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	issuer := &fakeIssuer{keys: map[string]*rsa.PrivateKey{"key-1": newRsaKey(t)}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, discoveryDocument{
+			Issuer:                issuer.server.URL,
+			AuthorizationEndpoint: issuer.server.URL + "/authorize",
+			TokenEndpoint:         issuer.server.URL + "/token",
+			JwksUri:               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		// A test that set jwksHold receives a channel to release the request with.
+		issuer.mutex.Lock()
+		hold := issuer.jwksHold
+		issuer.mutex.Unlock()
+		if hold != nil {
+			release := make(chan struct{})
+			hold <- release
+			<-release
+		}
+		issuer.mutex.Lock()
+		defer issuer.mutex.Unlock()
+		issuer.jwksFetches++
+		var keySet jsonWebKeySet
+		for kid, key := range issuer.keys {
+			keySet.Keys = append(keySet.Keys, jsonWebKey{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		writeJSON(w, http.StatusOK, keySet)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mutex.Lock()
+		defer issuer.mutex.Unlock()
+		if r.PostFormValue("code") != "code" || codeChallenge(r.PostFormValue("code_verifier")) != issuer.challenge {
+			writeJSON(w, http.StatusBadRequest, tokenResponse{Error: "invalid_grant"})
+			return
+		}
+		writeJSON(w, http.StatusOK, tokenResponse{AccessToken: "access", IdToken: issuer.idToken, TokenType: "Bearer"})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *fakeIssuer) provider() *oidcProvider {
+	return newOidc(configure.SsoProvider{
+		Issuer:       i.server.URL,
+		ClientId:     testClientId,
+		ClientSecret: "secret",
+	}, "http://localhost:3000/auth/sso/callback/oidc", i.server.Client()).(*oidcProvider)
+}
+
+// claims are the claims of a valid ID token for request.
+func (i *fakeIssuer) claims(request AuthRequest) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            i.server.URL,
+		"aud":            testClientId,
+		"sub":            "subject-1",
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          request.Nonce,
+		"email":          "alice@example.com",
+		"email_verified": "true",
+	}
+}
+
+func (i *fakeIssuer) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(i.keys[kid])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// login runs the authorization request against the provider and answers the following exchange with
+// idToken, whose code challenge is the one the authorization URL carried.
+func (i *fakeIssuer) login(t *testing.T, provider Provider, request AuthRequest, idToken string) {
+	t.Helper()
+	if err := i.authorize(provider, request, idToken); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// authorize plays the part of the user at the authorization endpoint: the next token request
+// returns idToken if it carries the verifier of request.
+func (i *fakeIssuer) authorize(provider Provider, request AuthRequest, idToken string) error {
+	authorizationUrl, err := provider.AuthCodeURL(context.Background(), request)
+	if err != nil {
+		return err
+	}
+	target, err := url.Parse(authorizationUrl)
+	if err != nil {
+		return err
+	}
+	query := target.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("nonce") != request.Nonce || query.Get("state") != request.State {
+		return fmt.Errorf("authorization URL %s lacks the PKCE, nonce or state parameters", authorizationUrl)
+	}
+	i.mutex.Lock()
+	i.challenge, i.idToken = query.Get("code_challenge"), idToken
+	i.mutex.Unlock()
+	return nil
+}
+
+func newAuthRequest(t *testing.T) AuthRequest {
+	t.Helper()
+	request, err := (&service{}).NewAuthRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+func newRsaKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func TestOidcExchange(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider()
+	request := newAuthRequest(t)
+	issuer.login(t, provider, request, issuer.sign(t, "key-1", issuer.claims(request)))
+	identity, err := provider.Exchange(context.Background(), "code", request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Subject != "subject-1" || identity.Email != "alice@example.com" || !identity.EmailVerified {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+}
+
+func TestOidcExchangeRequiresCodeVerifier(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider()
+	request := newAuthRequest(t)
+	issuer.login(t, provider, request, issuer.sign(t, "key-1", issuer.claims(request)))
+	// A code intercepted on its way back is useless without the verifier of the original request.
+	stolen := request
+	stolen.CodeVerifier = newAuthRequest(t).CodeVerifier
+	if _, err := provider.Exchange(context.Background(), "code", stolen); !errors.Is(err, ErrExchangeFailed) {
+		t.Fatalf("exchange with another verifier: got %v, want %v", err, ErrExchangeFailed)
+	}
+}
+
+func TestOidcExchangeRejectsInvalidIdTokens(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	tests := []struct {
+		name    string
+		idToken func(request AuthRequest) string
+	}{
+		{"wrong nonce", func(request AuthRequest) string {
+			claims := issuer.claims(request)
+			claims["nonce"] = "replayed"
+			return issuer.sign(t, "key-1", claims)
+		}},
+		{"wrong issuer", func(request AuthRequest) string {
+			claims := issuer.claims(request)
+			claims["iss"] = "https://attacker.example.com"
+			return issuer.sign(t, "key-1", claims)
+		}},
+		{"wrong audience", func(request AuthRequest) string {
+			claims := issuer.claims(request)
+			claims["aud"] = "another-client"
+			return issuer.sign(t, "key-1", claims)
+		}},
+		{"expired", func(request AuthRequest) string {
+			claims := issuer.claims(request)
+			claims["iat"] = time.Now().Add(-time.Hour).Unix()
+			claims["exp"] = time.Now().Add(-10 * time.Minute).Unix()
+			return issuer.sign(t, "key-1", claims)
+		}},
+		{"alg none", func(request AuthRequest) string {
+			idToken, err := jwt.NewWithClaims(jwt.SigningMethodNone, issuer.claims(request)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return idToken
+		}},
+		{"alg HS256", func(request AuthRequest) string {
+			// Signed with the client secret, which a confused verifier would accept as an HMAC key.
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, issuer.claims(request))
+			token.Header["kid"] = "key-1"
+			idToken, err := token.SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return idToken
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := issuer.provider()
+			request := newAuthRequest(t)
+			issuer.login(t, provider, request, test.idToken(request))
+			if _, err := provider.Exchange(context.Background(), "code", request); !errors.Is(err, ErrIdTokenInvalid) {
+				t.Fatalf("got %v, want %v", err, ErrIdTokenInvalid)
+			}
+		})
+	}
+}
+
+func TestOidcUnknownKeyIdRefetchesKeys(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider()
+	request := newAuthRequest(t)
+	issuer.login(t, provider, request, issuer.sign(t, "key-1", issuer.claims(request)))
+	if _, err := provider.Exchange(context.Background(), "code", request); err != nil {
+		t.Fatal(err)
+	}
+
+	// The provider rotates its keys; a token signed with the new one is unknown to the cache.
+	issuer.mutex.Lock()
+	issuer.keys["key-2"] = newRsaKey(t)
+	issuer.mutex.Unlock()
+	request = newAuthRequest(t)
+	issuer.login(t, provider, request, issuer.sign(t, "key-2", issuer.claims(request)))
+	// Within keysRefreshInterval of the last fetch an unknown key id does not reach the provider.
+	if _, err := provider.Exchange(context.Background(), "code", request); !errors.Is(err, ErrIdTokenInvalid) {
+		t.Fatalf("got %v, want %v", err, ErrIdTokenInvalid)
+	}
+	if issuer.jwksFetches != 1 {
+		t.Fatalf("keys fetched %d times, want 1", issuer.jwksFetches)
+	}
+
+	provider.mutex.Lock()
+	provider.keysFetchedAt = time.Now().Add(-2 * keysRefreshInterval)
+	provider.mutex.Unlock()
+	if _, err := provider.Exchange(context.Background(), "code", request); err != nil {
+		t.Fatal(err)
+	}
+	if issuer.jwksFetches != 2 {
+		t.Fatalf("keys fetched %d times, want 2", issuer.jwksFetches)
+	}
+}
+
+func TestOidcKeyRefreshDoesNotBlockCachedKeys(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider()
+	request := newAuthRequest(t)
+	issuer.login(t, provider, request, issuer.sign(t, "key-1", issuer.claims(request)))
+	if _, err := provider.Exchange(context.Background(), "code", request); err != nil {
+		t.Fatal(err)
+	}
+
+	// The keys go stale and the next exchange refreshes them against a provider that hangs.
+	issuer.mutex.Lock()
+	issuer.jwksHold = make(chan chan struct{})
+	issuer.mutex.Unlock()
+	provider.mutex.Lock()
+	provider.keysFetchedAt = time.Now().Add(-2 * metadataTTL)
+	provider.mutex.Unlock()
+	request = newAuthRequest(t)
+	issuer.login(t, provider, request, issuer.sign(t, "key-1", issuer.claims(request)))
+	refreshed := make(chan error, 1)
+	go func() {
+		_, err := provider.Exchange(context.Background(), "code", request)
+		refreshed <- err
+	}()
+	release := <-issuer.jwksHold
+
+	// Meanwhile another login is verified with the cached key instead of waiting for the refresh.
+	request = newAuthRequest(t)
+	idToken := issuer.sign(t, "key-1", issuer.claims(request))
+	cached := make(chan error, 1)
+	go func() {
+		err := issuer.authorize(provider, request, idToken)
+		if err == nil {
+			_, err = provider.Exchange(context.Background(), "code", request)
+		}
+		cached <- err
+	}()
+	select {
+	case err := <-cached:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		close(release)
+		t.Fatal("exchange waited for the key refresh")
+	}
+	close(release)
+	if err := <-refreshed; err != nil {
+		t.Fatal(err)
+	}
+	if issuer.jwksFetches != 2 {
+		t.Fatalf("keys fetched %d times, want 2", issuer.jwksFetches)
+	}
+}
+
+func TestIdentityLinkTarget(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider()
+	exchange := func(emailVerified interface{}) *Identity {
+		request := newAuthRequest(t)
+		claims := issuer.claims(request)
+		claims["email_verified"] = emailVerified
+		issuer.login(t, provider, request, issuer.sign(t, "key-1", claims))
+		identity, err := provider.Exchange(context.Background(), "code", request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return identity
+	}
+	verified, unverified := exchange(true), exchange("false")
+	tests := []struct {
+		name          string
+		identity      *Identity
+		emailVerified []bool
+		target        int
+		err           error
+	}{
+		{"unverified at the provider", unverified, []bool{true}, 0, ErrEmailNotVerified},
+		{"no local account", verified, nil, -1, nil},
+		{"verified local account", verified, []bool{false, true}, 1, nil},
+		{"unverified local account", verified, []bool{false}, 0, ErrAccountNotLinkable},
+		{"several verified local accounts", verified, []bool{true, true}, 0, ErrAccountNotLinkable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target, err := test.identity.LinkTarget(test.emailVerified)
+			if !errors.Is(err, test.err) || err == nil && target != test.target {
+				t.Fatalf("got %d, %v, want %d, %v", target, err, test.target, test.err)
+			}
+		})
+	}
+}
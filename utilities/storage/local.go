@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// localService keeps objects as files below root. Content types are not persisted; they are
+// sniffed again when an object is read.
+type localService struct {
+	root string
+}
+
+func newLocal(root string) Service {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		logger.Fatal().Err(err).Msg("Storage local init error")
+	}
+	return &localService{root: root}
+}
+
+func (s *localService) InitGlobal() {
+	global = s
+}
+
+// PutObject writes to a temporary file first so readers never see a partially written object.
+func (s *localService) PutObject(key string, reader io.Reader, size int64, _ string) error {
+	filename, err := s.filename(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(filename), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	written, err := io.Copy(tmp, io.LimitReader(reader, size+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("object size is %d bytes, expected %d", written, size)
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+func (s *localService) GetObject(name string) (io.ReadCloser, ObjectInfo, error) {
+	filename, err := s.filename(name)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ObjectInfo{}, ErrObjectNotFound
+		}
+		return nil, ObjectInfo{}, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, ObjectInfo{}, err
+	}
+	if stat.IsDir() {
+		_ = f.Close()
+		return nil, ObjectInfo{}, ErrObjectNotFound
+	}
+	head, err := readHead(f)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, ObjectInfo{}, err
+	}
+	return f, ObjectInfo{ContentType: http.DetectContentType(head), Size: stat.Size()}, nil
+}
+
+// CopyObject ignores contentType like PutObject; the copy is written through PutObject so it
+// appears at once.
+func (s *localService) CopyObject(src, dst, _ string) error {
+	object, info, err := s.GetObject(src)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+	return s.PutObject(dst, object, info.Size, "")
+}
+
+func (s *localService) DeleteObject(name string) error {
+	filename, err := s.filename(name)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(filename); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *localService) PresignedPutObject(name string, size int64, expires time.Duration) (*url.URL, error) {
+	return presignedPutUrl(name, size, expires)
+}
+
+func (s *localService) PresignedGetObject(name, filename, contentType string, expires time.Duration) (*url.URL, error) {
+	return presignedGetUrl(name, filename, contentType, expires)
+}
+
+func (s *localService) PublicUrl(name string) string {
+	return publicUrl(name)
+}
+
+func (s *localService) StatObject(name string) (int64, error) {
+	filename, err := s.filename(name)
+	if err != nil {
+		return 0, err
+	}
+	stat, err := os.Stat(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, ErrObjectNotFound
+		}
+		return 0, err
+	}
+	return stat.Size(), nil
+}
+
+func (s *localService) ReadObjectHead(name string, length int64) ([]byte, error) {
+	return readObjectHead(s.GetObject, name, length)
+}
+
+func (s *localService) filename(name string) (string, error) {
+	if err := validateKey(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(name)), nil
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// memoryService keeps objects in process memory. It is meant for tests and throwaway
+// environments: everything is lost on restart and nothing is shared between instances.
+type memoryService struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	contentType string
+	data        []byte
+}
+
+func newMemory() Service {
+	return &memoryService{objects: make(map[string]memoryObject)}
+}
+
+func (s *memoryService) InitGlobal() {
+	global = s
+}
+
+func (s *memoryService) PutObject(key string, reader io.Reader, size int64, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, size+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return fmt.Errorf("object size is %d bytes, expected %d", len(data), size)
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = memoryObject{contentType: contentType, data: data}
+	return nil
+}
+
+func (s *memoryService) GetObject(name string) (io.ReadCloser, ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	object, ok := s.objects[name]
+	if !ok {
+		return nil, ObjectInfo{}, ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(object.data)), ObjectInfo{ContentType: object.contentType, Size: int64(len(object.data))}, nil
+}
+
+func (s *memoryService) CopyObject(src, dst, contentType string) error {
+	if err := validateKey(dst); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects[src]
+	if !ok {
+		return ErrObjectNotFound
+	}
+	s.objects[dst] = memoryObject{contentType: contentType, data: object.data}
+	return nil
+}
+
+func (s *memoryService) DeleteObject(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, name)
+	return nil
+}
+
+func (s *memoryService) PresignedPutObject(name string, size int64, expires time.Duration) (*url.URL, error) {
+	return presignedPutUrl(name, size, expires)
+}
+
+func (s *memoryService) PresignedGetObject(name, filename, contentType string, expires time.Duration) (*url.URL, error) {
+	return presignedGetUrl(name, filename, contentType, expires)
+}
+
+func (s *memoryService) PublicUrl(name string) string {
+	return publicUrl(name)
+}
+
+func (s *memoryService) StatObject(name string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	object, ok := s.objects[name]
+	if !ok {
+		return 0, ErrObjectNotFound
+	}
+	return int64(len(object.data)), nil
+}
+
+func (s *memoryService) ReadObjectHead(name string, length int64) ([]byte, error) {
+	return readObjectHead(s.GetObject, name, length)
+}
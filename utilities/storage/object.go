@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// sniffLength is how many bytes http.DetectContentType looks at.
+const sniffLength = 512
+
+// readObjectHead returns up to the first length bytes of name read through get.
+func readObjectHead(get func(name string) (io.ReadCloser, ObjectInfo, error), name string, length int64) ([]byte, error) {
+	object, _, err := get(name)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+	return io.ReadAll(io.LimitReader(object, length))
+}
+
+func readHead(r io.Reader) ([]byte, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// validateKey rejects keys that are not clean relative paths, so no driver can be steered outside
+// of its own namespace.
+func validateKey(name string) error {
+	if name == "" || strings.ContainsRune(name, 0) || path.Clean(name) != name || path.IsAbs(name) ||
+		name == ".." || strings.HasPrefix(name, "../") || strings.Contains(name, "\\") {
+		return ErrInvalidKey
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type s3Service struct {
+	Client *minio.Client
+	Bucket string
+}
+
+func newS3() Service {
+	minioClient, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds: credentials.NewStaticV4(cfg.S3AccessKeyId, cfg.S3SecretAccessKey, ""),
+	})
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Storage S3 init error")
+	}
+	return &s3Service{
+		Client: minioClient,
+		Bucket: cfg.S3BucketName,
+	}
+}
+
+func (s *s3Service) InitGlobal() {
+	global = s
+}
+
+// PutObject stores reader under a key chosen by the caller, e.g. for derived files such as thumbnails.
+func (s *s3Service) PutObject(key string, reader io.Reader, size int64, contentType string) error {
+	_, err := s.Client.PutObject(context.Background(), s.Bucket, key, reader, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+func (s *s3Service) GetObject(name string) (io.ReadCloser, ObjectInfo, error) {
+	object, err := s.Client.GetObject(context.Background(), s.Bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	info, err := object.Stat()
+	if err != nil {
+		_ = object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ObjectInfo{}, ErrObjectNotFound
+		}
+		return nil, ObjectInfo{}, err
+	}
+	return object, ObjectInfo{ContentType: info.ContentType, Size: info.Size}, nil
+}
+
+// CopyObject copies src to dst within the bucket and stores contentType as the type of dst.
+func (s *s3Service) CopyObject(src, dst, contentType string) error {
+	_, err := s.Client.CopyObject(context.Background(), minio.CopyDestOptions{
+		Bucket:          s.Bucket,
+		Object:          dst,
+		ReplaceMetadata: true,
+		UserMetadata:    map[string]string{"Content-Type": contentType},
+	}, minio.CopySrcOptions{Bucket: s.Bucket, Object: src})
+	return err
+}
+
+func (s *s3Service) DeleteObject(name string) error {
+	return s.Client.RemoveObject(context.Background(), s.Bucket, name, minio.RemoveObjectOptions{})
+}
+
+// PresignedPutObject signs an upload of exactly size bytes to name; the client must send the same Content-Length.
+func (s *s3Service) PresignedPutObject(name string, size int64, expires time.Duration) (*url.URL, error) {
+	headers := http.Header{}
+	headers.Set("Content-Length", strconv.FormatInt(size, 10))
+	return s.Client.PresignHeader(context.Background(), http.MethodPut, s.Bucket, name, expires, nil, headers)
+}
+
+// PresignedGetObject signs a download of name that is served as contentType and saved as filename by browsers.
+func (s *s3Service) PresignedGetObject(name, filename, contentType string, expires time.Duration) (*url.URL, error) {
+	params := url.Values{}
+	params.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	params.Set("response-content-type", contentType)
+	return s.Client.PresignedGetObject(context.Background(), s.Bucket, name, expires, params)
+}
+
+// PublicUrl serves name straight from the bucket, which must allow anonymous reads of public objects.
+func (s *s3Service) PublicUrl(name string) string {
+	publicUrl, err := url.JoinPath(cfg.S3Prefix, name)
+	if err != nil {
+		return ""
+	}
+	return publicUrl
+}
+
+func (s *s3Service) StatObject(name string) (int64, error) {
+	info, err := s.Client.StatObject(context.Background(), s.Bucket, name, minio.StatObjectOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return info.Size, nil
+}
+
+// ReadObjectHead returns up to the first length bytes of name, e.g. to sniff its content type.
+func (s *s3Service) ReadObjectHead(name string, length int64) ([]byte, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(0, length-1); err != nil {
+		return nil, err
+	}
+	object, err := s.Client.GetObject(context.Background(), s.Bucket, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+	return io.ReadAll(io.LimitReader(object, length))
+}
+
+func (s *s3Service) CheckBucketExists(bucketName string) (bool, error) {
+	return s.Client.BucketExists(context.Background(), bucketName)
+}
@@ -0,0 +1,28 @@
+package textsearch
+
+const (
+	// maxTerms bounds how many words of a query are searched for.
+	maxTerms = 10
+	// gramSize is the length, in runes, of the n-grams stored to back substring filters.
+	gramSize = 3
+	// snippetRadius is how many characters a snippet keeps on each side of the first match.
+	snippetRadius = 60
+
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+)
+
+type Service interface {
+	Normalize(value string) string
+	Grams(normalized string) []string
+	Terms(query string) []string
+	MongoSearch(terms []string) string
+	Matches(text string, terms []string) bool
+	Snippet(text string, terms []string) string
+}
+
+type service struct{}
+
+func New() Service {
+	return &service{}
+}
@@ -0,0 +1,140 @@
+package textsearch
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+
+	"jira-clone-api/utilities/tool"
+)
+
+// Normalize lower-cases value and strips its Vietnamese accents. Searchable fields are stored next to
+// their normalized copy so that filters can match it without expanding every vowel into a regex class.
+func (s *service) Normalize(value string) string {
+	return tool.New().DeaccentVietnameseString(value)
+}
+
+// Grams returns the distinct trigrams of a normalized value, or nothing when it is shorter than one.
+func (s *service) Grams(normalized string) []string {
+	value := []rune(normalized)
+	var grams []string
+	for i := 0; i+gramSize <= len(value); i++ {
+		gram := string(value[i : i+gramSize])
+		if !slices.Contains(grams, gram) {
+			grams = append(grams, gram)
+		}
+	}
+	return grams
+}
+
+// Terms splits query into lower-cased, accent-free words. Quotes and leading minus signs are dropped,
+// so users cannot reach the phrase and negation syntax of Mongo text search.
+func (s *service) Terms(query string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		term := s.Normalize(word)
+		if term == "" || slices.Contains(terms, term) {
+			continue
+		}
+		terms = append(terms, term)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// MongoSearch builds the $text search string for terms. Text indexes ignore diacritics but treat
+// "đ" as its own letter, so terms containing "d" are searched in their "đ" spelling as well.
+func (s *service) MongoSearch(terms []string) string {
+	search := make([]string, 0, len(terms))
+	for _, term := range terms {
+		search = append(search, term)
+		if strings.ContainsRune(term, 'd') {
+			search = append(search, strings.ReplaceAll(term, "d", "đ"))
+		}
+	}
+	return strings.Join(search, " ")
+}
+
+// Matches reports whether any term occurs in text the way Snippet would highlight it.
+func (s *service) Matches(text string, terms []string) bool {
+	_, _, first := match(text, terms)
+	return first != -1
+}
+
+// Snippet cuts text around the first matched term and wraps every match in <mark>. Matching is
+// case- and accent-insensitive; everything outside the marks is HTML-escaped.
+func (s *service) Snippet(text string, terms []string) string {
+	original, marked, first := match(text, terms)
+	start, end := 0, len(original)
+	if first > snippetRadius {
+		start = first - snippetRadius
+	}
+	if end-start > 2*snippetRadius {
+		end = start + 2*snippetRadius
+	}
+	var snippet strings.Builder
+	if start > 0 {
+		snippet.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			snippet.WriteString(markOpen + html.EscapeString(string(original[i:j])) + markClose)
+		} else {
+			snippet.WriteString(html.EscapeString(string(original[i:j])))
+		}
+		i = j
+	}
+	if end < len(original) {
+		snippet.WriteString("…")
+	}
+	return snippet.String()
+}
+
+// match marks the runes of text covered by a term and returns the index of the first match, or -1.
+func match(text string, terms []string) ([]rune, []bool, int) {
+	original := []rune(text)
+	folded := make([]rune, len(original))
+	for i, r := range original {
+		folded[i] = foldRune(r)
+	}
+	marked := make([]bool, len(original))
+	first := -1
+	for _, term := range terms {
+		termRunes := []rune(term)
+		for i := 0; i+len(termRunes) <= len(folded); i++ {
+			if !slices.Equal(folded[i:i+len(termRunes)], termRunes) || !isWordStart(folded, i) {
+				continue
+			}
+			for j := i; j < i+len(termRunes); j++ {
+				marked[j] = true
+			}
+			if first == -1 || i < first {
+				first = i
+			}
+		}
+	}
+	return original, marked, first
+}
+
+// foldRune maps a rune the way Terms folds words, keeping a one-to-one mapping with the original text.
+func foldRune(r rune) rune {
+	folded := []rune(tool.New().DeaccentVietnameseString(string(r)))
+	if len(folded) != 1 {
+		return unicode.ToLower(r)
+	}
+	return folded[0]
+}
+
+// isWordStart mirrors text indexes, which match whole words from their first letter.
+func isWordStart(text []rune, i int) bool {
+	return i == 0 || (!unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]))
+}
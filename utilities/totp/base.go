@@ -0,0 +1,36 @@
+package totp
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// secretSize is the length in bytes of generated secrets, the size RFC 4226 recommends for SHA-1.
+	secretSize = 20
+	digits     = 6
+	period     = 30 * time.Second
+	// skew is how many periods before and after the current one are still accepted, to absorb clock drift.
+	skew = 1
+
+	recoveryCodeSize = 10
+)
+
+var ErrSecretInvalid = errors.New("totp secret is not valid base32")
+
+// Service implements RFC 6238 time-based one-time passwords with the parameters authenticator apps
+// assume by default: HMAC-SHA1, 6 digits, 30 second periods.
+type Service interface {
+	GenerateSecret() (secret string, err error)
+	URI(issuer, account, secret string) string
+	// Validate reports the period the code belongs to, so that callers can refuse to accept a period twice.
+	Validate(secret, code string, at time.Time) (step int64, ok bool, err error)
+	GenerateRecoveryCodes(count int) (codes []string, err error)
+	NormalizeRecoveryCode(code string) string
+}
+
+type service struct{}
+
+func New() Service {
+	return &service{}
+}